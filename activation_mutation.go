@@ -0,0 +1,154 @@
+// blueprint/activation_mutation.go
+package blueprint
+
+import (
+	"fmt"
+	"slices"
+)
+
+// groupActivations are normalized across a whole dense layer and so cannot be applied to single values.
+var groupActivations = []string{"softmax", "log_softmax"}
+
+// ConvActivations returns the activations a convolutional layer can use: every supported activation
+// except those normalized across a group of neurons.
+func ConvActivations() []string {
+	return elementwiseActivations()
+}
+
+// elementwiseActivations returns every supported activation except those normalized across a group of
+// neurons. Activation mutations draw from these, since softmax belongs on output layers only.
+func elementwiseActivations() []string {
+	var allowed []string
+	for _, activation := range SupportedActivations {
+		if !slices.Contains(groupActivations, activation) {
+			allowed = append(allowed, activation)
+		}
+	}
+	return allowed
+}
+
+// SetLayerActivation sets the activation of every neuron of a dense hidden layer, or the activation of a
+// conv hidden layer.
+func (bp *Blueprint) SetLayerActivation(layerIndex int, activation string) error {
+	if layerIndex < 0 || layerIndex >= len(bp.Config.Layers.Hidden) {
+		return fmt.Errorf("hidden layer index %d out of range [0, %d)", layerIndex, len(bp.Config.Layers.Hidden))
+	}
+	layer := &bp.Config.Layers.Hidden[layerIndex]
+	switch layer.LayerType {
+	case "dense":
+		if !slices.Contains(SupportedActivations, activation) {
+			return fmt.Errorf("unknown activation type %q", activation)
+		}
+		for id, neuron := range layer.Neurons {
+			neuron.ActivationType = activation
+			layer.Neurons[id] = neuron
+		}
+	case "conv":
+		if !slices.Contains(ConvActivations(), activation) {
+			return fmt.Errorf("activation type %q is not supported by conv layers", activation)
+		}
+		layer.ActivationType = activation
+	default:
+		return fmt.Errorf("layer %d is %q and has no activation", layerIndex, layer.LayerType)
+	}
+	return nil
+}
+
+// MutateNeuronActivation gives a random neuron of a random dense hidden layer a different activation
+// drawn from allowed, or from SupportedActivations when allowed is empty. Softmax and log_softmax are
+// never chosen.
+func (bp *Blueprint) MutateNeuronActivation(allowed ...string) error {
+	return bp.mutateNeuronActivation(allowed, &MutationRecord{})
+}
+
+// mutateNeuronActivation implements MutateNeuronActivation, recording the neuron and its new activation.
+func (bp *Blueprint) mutateNeuronActivation(allowed []string, rec *MutationRecord) error {
+	rng := bp.Rand()
+	var candidates []int
+	for i, layer := range bp.Config.Layers.Hidden {
+		if layer.LayerType == "dense" && len(layer.Neurons) > 0 {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("no dense hidden layer to mutate")
+	}
+
+	rec.LayerIndex = candidates[rng.Intn(len(candidates))]
+	layer := bp.Config.Layers.Hidden[rec.LayerIndex]
+	neuronIDs := sortedKeys(layer.Neurons)
+	rec.NeuronID = neuronIDs[rng.Intn(len(neuronIDs))]
+	neuron := layer.Neurons[rec.NeuronID]
+
+	choices := activationChoices(allowed, elementwiseActivations(), neuron.ActivationType)
+	if len(choices) == 0 {
+		return fmt.Errorf("no other allowed activation for neuron %q", rec.NeuronID)
+	}
+	rec.Activation = choices[rng.Intn(len(choices))]
+	neuron.ActivationType = rec.Activation
+	layer.Neurons[rec.NeuronID] = neuron
+	return nil
+}
+
+// MutateLayerActivation sets a random dense or conv hidden layer to a different activation drawn from
+// allowed, or from SupportedActivations when allowed is empty. Softmax and log_softmax are never chosen.
+func (bp *Blueprint) MutateLayerActivation(allowed ...string) error {
+	return bp.mutateLayerActivation(allowed, &MutationRecord{})
+}
+
+// mutateLayerActivation implements MutateLayerActivation, recording the layer and its new activation.
+func (bp *Blueprint) mutateLayerActivation(allowed []string, rec *MutationRecord) error {
+	rng := bp.Rand()
+	var candidates []int
+	for i, layer := range bp.Config.Layers.Hidden {
+		if layer.LayerType == "dense" || layer.LayerType == "conv" {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("no dense or conv hidden layer to mutate")
+	}
+
+	rec.LayerIndex = candidates[rng.Intn(len(candidates))]
+	layer := bp.Config.Layers.Hidden[rec.LayerIndex]
+	rec.LayerType = layer.LayerType
+	current := layer.convActivation()
+	if layer.LayerType == "dense" {
+		current = layer.denseActivation()
+	}
+	choices := activationChoices(allowed, elementwiseActivations(), current)
+	if len(choices) == 0 {
+		return fmt.Errorf("no other allowed activation for layer %d", rec.LayerIndex)
+	}
+	rec.Activation = choices[rng.Intn(len(choices))]
+	return bp.SetLayerActivation(rec.LayerIndex, rec.Activation)
+}
+
+// denseActivation returns the activation shared by every neuron of a dense layer, or "" when the neurons
+// differ.
+func (layer Layer) denseActivation() string {
+	shared := ""
+	for i, neuronID := range sortedKeys(layer.Neurons) {
+		activation := layer.Neurons[neuronID].ActivationType
+		if i > 0 && activation != shared {
+			return ""
+		}
+		shared = activation
+	}
+	return shared
+}
+
+// activationChoices returns the entries of allowed (or of supported when allowed is empty) that are
+// supported and differ from current.
+func activationChoices(allowed, supported []string, current string) []string {
+	if len(allowed) == 0 {
+		allowed = supported
+	}
+	var choices []string
+	for _, activation := range allowed {
+		if activation != current && slices.Contains(supported, activation) && !slices.Contains(choices, activation) {
+			choices = append(choices, activation)
+		}
+	}
+	return choices
+}
@@ -0,0 +1,46 @@
+package blueprint
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestActivationMutationsSkipGroupActivations(t *testing.T) {
+	for seed := int64(0); seed < 50; seed++ {
+		bp := newTestNetwork(seed)
+		if err := bp.MutateNeuronActivation(); err != nil {
+			t.Fatal(err)
+		}
+		if err := bp.MutateLayerActivation(); err != nil {
+			t.Fatal(err)
+		}
+		for _, neuron := range bp.Config.Layers.Hidden[0].Neurons {
+			if slices.Contains(groupActivations, neuron.ActivationType) {
+				t.Fatalf("seed %d: hidden neuron mutated to %s", seed, neuron.ActivationType)
+			}
+		}
+	}
+
+	bp := newTestNetwork(1)
+	if err := bp.MutateNeuronActivation("softmax", "log_softmax"); err == nil {
+		t.Fatal("mutating to softmax only succeeded")
+	}
+}
+
+func TestMutateLayerActivationChangesActivation(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		bp := newTestNetwork(seed)
+		if err := bp.MutateLayerActivation("relu", "tanh"); err != nil {
+			t.Fatal(err)
+		}
+		// Every hidden neuron starts as relu, so tanh is the only change
+		if got := bp.Config.Layers.Hidden[0].denseActivation(); got != "tanh" {
+			t.Fatalf("seed %d: layer activation %q, want tanh", seed, got)
+		}
+	}
+
+	bp := newTestNetwork(1)
+	if err := bp.MutateLayerActivation("relu"); err == nil {
+		t.Fatal("mutating a relu layer to relu succeeded")
+	}
+}
@@ -0,0 +1,380 @@
+// blueprint/backprop.go
+package blueprint
+
+import "strconv"
+
+// Gradients holds the loss derivatives for every trainable parameter in the network.
+// Layers are indexed like trainableLayers: hidden layers in order, followed by the output layer.
+type Gradients struct {
+	Layers []LayerGradients `json:"layers"`
+}
+
+// LayerGradients holds the gradients for the parameters of a single layer.
+type LayerGradients struct {
+	Neurons   map[string]NeuronGradient `json:"neurons,omitempty"`   // For dense layers
+	Filters   []FilterGradient          `json:"filters,omitempty"`   // For convolutional layers
+	LSTMCells []LSTMCellGradient        `json:"lstmCells,omitempty"` // For LSTM layers
+}
+
+// NeuronGradient holds the gradients for a neuron's incoming connections and bias.
+type NeuronGradient struct {
+	Connections map[string]float64 `json:"connections"`
+	Bias        float64            `json:"bias"`
+}
+
+// FilterGradient holds the gradients for a convolutional filter's kernel and bias.
+type FilterGradient struct {
+	Weights [][]float64 `json:"weights"`
+	Bias    float64     `json:"bias"`
+}
+
+// LSTMCellGradient holds the gradients for an LSTM cell's gate weights and shared bias.
+type LSTMCellGradient struct {
+	InputWeights  []float64 `json:"inputWeights"`
+	ForgetWeights []float64 `json:"forgetWeights"`
+	OutputWeights []float64 `json:"outputWeights"`
+	CellWeights   []float64 `json:"cellWeights"`
+	Bias          float64   `json:"bias"`
+}
+
+// Add accumulates other into g, so gradients from several samples can be combined into one update.
+func (g *Gradients) Add(other *Gradients) {
+	for len(g.Layers) < len(other.Layers) {
+		g.Layers = append(g.Layers, LayerGradients{})
+	}
+	for li, layerGrads := range other.Layers {
+		target := &g.Layers[li]
+
+		if len(layerGrads.Neurons) > 0 && target.Neurons == nil {
+			target.Neurons = make(map[string]NeuronGradient, len(layerGrads.Neurons))
+		}
+		for neuronID, neuronGrad := range layerGrads.Neurons {
+			sum, ok := target.Neurons[neuronID]
+			if !ok {
+				sum.Connections = make(map[string]float64, len(neuronGrad.Connections))
+			}
+			for inputID, grad := range neuronGrad.Connections {
+				sum.Connections[inputID] += grad
+			}
+			sum.Bias += neuronGrad.Bias
+			target.Neurons[neuronID] = sum
+		}
+
+		for fi, filterGrad := range layerGrads.Filters {
+			if fi >= len(target.Filters) {
+				target.Filters = append(target.Filters, FilterGradient{})
+			}
+			sum := &target.Filters[fi]
+			for i := range filterGrad.Weights {
+				if i >= len(sum.Weights) {
+					sum.Weights = append(sum.Weights, make([]float64, len(filterGrad.Weights[i])))
+				}
+				addVector(sum.Weights[i], filterGrad.Weights[i])
+			}
+			sum.Bias += filterGrad.Bias
+		}
+
+		for ci, cellGrad := range layerGrads.LSTMCells {
+			if ci >= len(target.LSTMCells) {
+				target.LSTMCells = append(target.LSTMCells, LSTMCellGradient{
+					InputWeights:  make([]float64, len(cellGrad.InputWeights)),
+					ForgetWeights: make([]float64, len(cellGrad.ForgetWeights)),
+					OutputWeights: make([]float64, len(cellGrad.OutputWeights)),
+					CellWeights:   make([]float64, len(cellGrad.CellWeights)),
+				})
+			}
+			sum := &target.LSTMCells[ci]
+			addVector(sum.InputWeights, cellGrad.InputWeights)
+			addVector(sum.ForgetWeights, cellGrad.ForgetWeights)
+			addVector(sum.OutputWeights, cellGrad.OutputWeights)
+			addVector(sum.CellWeights, cellGrad.CellWeights)
+			sum.Bias += cellGrad.Bias
+		}
+	}
+}
+
+// Scale multiplies every gradient by factor, for example to average an accumulated mini-batch.
+func (g *Gradients) Scale(factor float64) {
+	scaleVector := func(values []float64) {
+		for i := range values {
+			values[i] *= factor
+		}
+	}
+	for li := range g.Layers {
+		layerGrads := &g.Layers[li]
+		for neuronID, neuronGrad := range layerGrads.Neurons {
+			for inputID := range neuronGrad.Connections {
+				neuronGrad.Connections[inputID] *= factor
+			}
+			neuronGrad.Bias *= factor
+			layerGrads.Neurons[neuronID] = neuronGrad
+		}
+		for fi := range layerGrads.Filters {
+			for i := range layerGrads.Filters[fi].Weights {
+				scaleVector(layerGrads.Filters[fi].Weights[i])
+			}
+			layerGrads.Filters[fi].Bias *= factor
+		}
+		for ci := range layerGrads.LSTMCells {
+			cellGrad := &layerGrads.LSTMCells[ci]
+			scaleVector(cellGrad.InputWeights)
+			scaleVector(cellGrad.ForgetWeights)
+			scaleVector(cellGrad.OutputWeights)
+			scaleVector(cellGrad.CellWeights)
+			cellGrad.Bias *= factor
+		}
+	}
+}
+
+// addVector adds src into dst element-wise, ignoring entries beyond the shorter slice.
+func addVector(dst, src []float64) {
+	for i := range src {
+		if i < len(dst) {
+			dst[i] += src[i]
+		}
+	}
+}
+
+// layerTrace records what a layer received and produced during a forward pass.
+type layerTrace struct {
+	input          interface{}
+	preActivations map[string]float64
+	output         interface{}
+	conv           *convTrace
+	lstm           *lstmTrace
+}
+
+// trainableLayers returns pointers to the hidden layers followed by the output layer.
+func (bp *Blueprint) trainableLayers() []*Layer {
+	layers := make([]*Layer, 0, len(bp.Config.Layers.Hidden)+1)
+	for i := range bp.Config.Layers.Hidden {
+		layers = append(layers, &bp.Config.Layers.Hidden[i])
+	}
+	return append(layers, &bp.Config.Layers.Output)
+}
+
+// ComputeGradients runs a forward pass and backpropagates the mean squared error against the targets.
+// It returns the gradients together with the loss value for the sample.
+func (bp *Blueprint) ComputeGradients(inputValues map[string]interface{}, targets map[string]float64) (*Gradients, float64, error) {
+	return bp.ComputeGradientsWithLoss(inputValues, targets, MeanSquaredError{})
+}
+
+// ComputeGradientsWithLoss runs a forward pass and backpropagates the given loss against the targets.
+func (bp *Blueprint) ComputeGradientsWithLoss(inputValues map[string]interface{}, targets map[string]float64, loss Loss) (*Gradients, float64, error) {
+	traces, outputs, err := bp.forwardTrace(inputValues)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	value, outputGrad, err := loss.Compute(outputs, targets)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	grads, err := bp.backpropagate(traces, outputGrad)
+	if err != nil {
+		return nil, 0, err
+	}
+	return grads, value, nil
+}
+
+// ApplyGradients performs a plain gradient descent update of the network parameters in place.
+func (bp *Blueprint) ApplyGradients(grads *Gradients, learningRate float64) {
+	bp.updateParameters(grads, func(_ string, value, grad float64) float64 {
+		return value - learningRate*grad
+	})
+}
+
+// updateParameters visits every parameter that has a gradient and stores the value returned by update.
+// The key passed to update identifies the parameter by layer index and neuron, filter or cell position,
+// so optimizers can keep per-parameter state across steps.
+func (bp *Blueprint) updateParameters(grads *Gradients, update func(key string, value, grad float64) float64) {
+	layers := bp.trainableLayers()
+	for li, layerGrads := range grads.Layers {
+		if li >= len(layers) {
+			break
+		}
+		layer := layers[li]
+		prefix := strconv.Itoa(li) + "/"
+
+		for neuronID, neuronGrad := range layerGrads.Neurons {
+			neuron, ok := layer.Neurons[neuronID]
+			if !ok {
+				continue
+			}
+			for inputID, grad := range neuronGrad.Connections {
+				if conn, ok := neuron.Connections[inputID]; ok {
+					conn.Weight = update(prefix+neuronID+"/"+inputID, conn.Weight, grad)
+					neuron.Connections[inputID] = conn
+				}
+			}
+			neuron.Bias = update(prefix+neuronID+"/bias", neuron.Bias, neuronGrad.Bias)
+			layer.Neurons[neuronID] = neuron
+		}
+
+		for fi, filterGrad := range layerGrads.Filters {
+			if fi >= len(layer.Filters) {
+				break
+			}
+			filter := &layer.Filters[fi]
+			filterPrefix := prefix + "filter" + strconv.Itoa(fi) + "/"
+			for i := range filterGrad.Weights {
+				for j, grad := range filterGrad.Weights[i] {
+					if i < len(filter.Weights) && j < len(filter.Weights[i]) {
+						key := filterPrefix + "w" + strconv.Itoa(i) + "_" + strconv.Itoa(j)
+						filter.Weights[i][j] = update(key, filter.Weights[i][j], grad)
+					}
+				}
+			}
+			filter.Bias = update(filterPrefix+"bias", filter.Bias, filterGrad.Bias)
+		}
+
+		for ci, cellGrad := range layerGrads.LSTMCells {
+			if ci >= len(layer.LSTMCells) {
+				break
+			}
+			cell := &layer.LSTMCells[ci]
+			cellPrefix := prefix + "lstm" + strconv.Itoa(ci) + "/"
+			updateVector := func(name string, weights, gradients []float64) {
+				for i, grad := range gradients {
+					if i < len(weights) {
+						weights[i] = update(cellPrefix+name+strconv.Itoa(i), weights[i], grad)
+					}
+				}
+			}
+			updateVector("input", cell.InputWeights, cellGrad.InputWeights)
+			updateVector("forget", cell.ForgetWeights, cellGrad.ForgetWeights)
+			updateVector("output", cell.OutputWeights, cellGrad.OutputWeights)
+			updateVector("cell", cell.CellWeights, cellGrad.CellWeights)
+			cell.Bias = update(cellPrefix+"bias", cell.Bias, cellGrad.Bias)
+		}
+	}
+}
+
+// TrainStep computes the gradients for one sample and applies them, returning the loss before the update.
+func (bp *Blueprint) TrainStep(inputValues map[string]interface{}, targets map[string]float64, learningRate float64) (float64, error) {
+	grads, loss, err := bp.ComputeGradients(inputValues, targets)
+	if err != nil {
+		return 0, err
+	}
+	bp.ApplyGradients(grads, learningRate)
+	return loss, nil
+}
+
+// forwardTrace runs the network like Feedforward while recording each layer's inputs and pre-activations.
+func (bp *Blueprint) forwardTrace(inputValues map[string]interface{}) ([]layerTrace, map[string]float64, error) {
+	data, err := bp.loadInputs(inputValues, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	layers := bp.trainableLayers()
+	traces := make([]layerTrace, len(layers))
+	for i, layer := range layers {
+		layerErr := func(err error) error {
+			return &LayerError{LayerIndex: i, LayerType: layer.LayerType, Err: err}
+		}
+
+		trace := layerTrace{input: data}
+		switch layer.LayerType {
+		case "dense":
+			inputValues, ok := data.(map[string]float64)
+			if !ok {
+				return nil, nil, layerErr(shapeMismatch("map[string]float64", data))
+			}
+			trace.preActivations, trace.output = bp.denseForward(*layer, inputValues)
+		case "conv":
+			var output map[string]float64
+			trace.conv, output = bp.convForward(*layer, data)
+			if trace.conv == nil {
+				return nil, nil, layerErr(shapeMismatch("[][]float64 or [][][]float64 image", data))
+			}
+			trace.output = output
+		case "lstm":
+			var output map[string]float64
+			trace.lstm, output = bp.lstmForward(*layer, data)
+			if trace.lstm == nil {
+				return nil, nil, layerErr(shapeMismatch("[][]float64 sequence or map[string]float64", data))
+			}
+			trace.output = output
+		default:
+			return nil, nil, layerErr(&UnknownLayerTypeError{LayerType: layer.LayerType})
+		}
+		traces[i] = trace
+		data = trace.output
+	}
+
+	outputs, ok := data.(map[string]float64)
+	if !ok {
+		outputLayer := bp.Config.Layers.Output
+		return nil, nil, &LayerError{
+			LayerIndex: len(bp.Config.Layers.Hidden),
+			LayerType:  outputLayer.LayerType,
+			Err:        shapeMismatch("map[string]float64", data),
+		}
+	}
+	return traces, outputs, nil
+}
+
+// backpropagate walks the recorded traces in reverse, turning the loss gradient on the outputs into parameter gradients.
+func (bp *Blueprint) backpropagate(traces []layerTrace, outputGrad map[string]float64) (*Gradients, error) {
+	layers := bp.trainableLayers()
+	grads := &Gradients{Layers: make([]LayerGradients, len(layers))}
+
+	var upstream interface{} = outputGrad
+	for i := len(layers) - 1; i >= 0; i-- {
+		layer := layers[i]
+		switch layer.LayerType {
+		case "dense":
+			gradOut, ok := upstream.(map[string]float64)
+			if !ok {
+				return nil, &LayerError{LayerIndex: i, LayerType: layer.LayerType, Err: shapeMismatch("map[string]float64 gradient", upstream)}
+			}
+			neuronGrads, inputGrad := bp.denseBackward(*layer, traces[i], gradOut)
+			grads.Layers[i].Neurons = neuronGrads
+			upstream = inputGrad
+		case "conv":
+			gradOut, ok := upstream.(map[string]float64)
+			if !ok {
+				return nil, &LayerError{LayerIndex: i, LayerType: layer.LayerType, Err: shapeMismatch("map[string]float64 gradient", upstream)}
+			}
+			filterGrads, inputGrad := bp.convBackward(*layer, traces[i].conv, gradOut)
+			grads.Layers[i].Filters = filterGrads
+			upstream = inputGrad
+		case "lstm":
+			gradOut, ok := upstream.(map[string]float64)
+			if !ok {
+				return nil, &LayerError{LayerIndex: i, LayerType: layer.LayerType, Err: shapeMismatch("map[string]float64 gradient", upstream)}
+			}
+			cellGrads, inputGrad := bp.lstmBackward(*layer, traces[i].lstm, gradOut)
+			grads.Layers[i].LSTMCells = cellGrads
+			upstream = inputGrad
+		default:
+			return nil, &LayerError{LayerIndex: i, LayerType: layer.LayerType, Err: &UnknownLayerTypeError{LayerType: layer.LayerType}}
+		}
+	}
+	return grads, nil
+}
+
+// denseBackward computes parameter gradients for a dense layer and the gradient with respect to its inputs.
+func (bp *Blueprint) denseBackward(layer Layer, trace layerTrace, gradOut map[string]float64) (map[string]NeuronGradient, map[string]float64) {
+	inputValues := trace.input.(map[string]float64)
+	neuronGrads := make(map[string]NeuronGradient, len(layer.Neurons))
+	inputGrad := make(map[string]float64)
+
+	deltas := bp.activationGradients(layer, trace.preActivations, trace.output.(map[string]float64), gradOut)
+
+	for neuronID, neuron := range layer.Neurons {
+		delta := deltas[neuronID]
+		neuronGrad := NeuronGradient{
+			Connections: make(map[string]float64, len(neuron.Connections)),
+			Bias:        delta,
+		}
+		for inputID, conn := range neuron.Connections {
+			neuronGrad.Connections[inputID] = delta * inputValues[inputID]
+			inputGrad[inputID] += delta * conn.Weight
+		}
+		neuronGrads[neuronID] = neuronGrad
+	}
+	return neuronGrads, inputGrad
+}
@@ -0,0 +1,107 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+// checkGradients compares every analytic gradient with a central finite difference of the loss.
+func checkGradients(t *testing.T, bp *Blueprint, input map[string]interface{}, targets map[string]float64, loss Loss) {
+	t.Helper()
+	grads, _, err := bp.ComputeGradientsWithLoss(input, targets, loss)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	analytic := make(map[string]float64)
+	bp.updateParameters(grads, func(key string, value, grad float64) float64 {
+		analytic[key] = grad
+		return value
+	})
+	shift := func(key string, delta float64) {
+		bp.updateParameters(grads, func(k string, value, _ float64) float64 {
+			if k == key {
+				return value + delta
+			}
+			return value
+		})
+	}
+
+	const h = 1e-6
+	for _, key := range sortedKeys(analytic) {
+		shift(key, h)
+		_, plus, err := bp.ComputeGradientsWithLoss(input, targets, loss)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shift(key, -2*h)
+		_, minus, err := bp.ComputeGradientsWithLoss(input, targets, loss)
+		if err != nil {
+			t.Fatal(err)
+		}
+		shift(key, h)
+
+		numeric := (plus - minus) / (2 * h)
+		if math.Abs(numeric-analytic[key]) > 1e-5*math.Max(1, math.Abs(numeric)) {
+			t.Errorf("%s: analytic gradient %v, numeric %v", key, analytic[key], numeric)
+		}
+	}
+	if len(analytic) == 0 {
+		t.Fatal("no gradients were computed")
+	}
+}
+
+// setActivations assigns the activations to the layer's neurons in natural order, cycling through them.
+func setActivations(layer *Layer, activations ...string) {
+	for i, neuronID := range sortedKeys(layer.Neurons) {
+		neuron := layer.Neurons[neuronID]
+		neuron.ActivationType = activations[i%len(activations)]
+		layer.Neurons[neuronID] = neuron
+	}
+}
+
+// targetsFor returns targets for every output of bp on input, cycling through values.
+func targetsFor(t *testing.T, bp *Blueprint, input map[string]interface{}, values ...float64) map[string]float64 {
+	t.Helper()
+	outputs, err := bp.FeedforwardWithError(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets := make(map[string]float64, len(outputs))
+	for i, outputID := range sortedKeys(outputs) {
+		targets[outputID] = values[i%len(values)]
+	}
+	return targets
+}
+
+func TestComputeGradientsDense(t *testing.T) {
+	bp := NewBlueprintWithSeed(&NetworkConfig{}, 1)
+	bp.CreateCustomNetworkConfig(3, 6, 2, []string{"sigmoid", "tanh"}, "test", "test", NormalInitializer{StdDev: 1})
+	setActivations(&bp.Config.Layers.Hidden[0], "tanh", "sigmoid", "swish", "softplus", "elu", "linear")
+
+	input := map[string]interface{}{"neuron0": 0.3, "neuron1": -0.7, "neuron2": 0.5}
+	checkGradients(t, bp, input, targetsFor(t, bp, input, 1, 0), MeanSquaredError{})
+	checkGradients(t, bp, input, targetsFor(t, bp, input, 1, 0), Huber{Delta: 0.1})
+}
+
+func TestTrainStepReducesLoss(t *testing.T) {
+	bp := NewBlueprintWithSeed(&NetworkConfig{}, 2)
+	bp.CreateCustomNetworkConfig(2, 4, 1, []string{"sigmoid"}, "test", "test", XavierInitializer{})
+	setActivations(&bp.Config.Layers.Hidden[0], "tanh")
+
+	input := map[string]interface{}{"neuron0": 1.0, "neuron1": -1.0}
+	targets := targetsFor(t, bp, input, 0.9)
+	first, err := bp.TrainStep(input, targets, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last float64
+	for i := 0; i < 50; i++ {
+		if last, err = bp.TrainStep(input, targets, 0.5); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if last >= first {
+		t.Fatalf("loss did not decrease: first %v, last %v", first, last)
+	}
+}
@@ -0,0 +1,175 @@
+// blueprint/batch.go
+package blueprint
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// FeedforwardBatch runs every sample through the network and returns the outputs in the same order.
+// Dense networks are compiled once and evaluated with reused buffers; other networks fall back to
+// Feedforward. Like Feedforward, missing dense inputs are treated as zero and values that are not float64
+// are rejected. workers <= 0 fans out across GOMAXPROCS goroutines and 1 runs sequentially.
+func (bp *Blueprint) FeedforwardBatch(inputs []map[string]interface{}, workers int) ([]map[string]float64, error) {
+	outputs := make([]map[string]float64, len(inputs))
+
+	compiled, err := bp.compileDense()
+	if err != nil {
+		err = runBatch(len(inputs), workers, func() func(int) error {
+			return func(i int) error {
+				var err error
+				if outputs[i], err = bp.feedforward(inputs[i], false); err != nil {
+					return fmt.Errorf("sample %d: %w", i, err)
+				}
+				return nil
+			}
+		})
+		return outputs, err
+	}
+
+	err = runBatch(len(inputs), workers, func() func(int) error {
+		cn := compiled.Clone()
+		values := make([]float64, len(cn.inputIDs))
+		return func(i int) error {
+			// Reject every value that is not a float64, like Feedforward, even under keys the network ignores
+			for key, raw := range inputs[i] {
+				if _, ok := raw.(float64); !ok {
+					return fmt.Errorf("sample %d: %w", i, &InputTypeError{Key: key, Expected: "float64", Actual: fmt.Sprintf("%T", raw)})
+				}
+			}
+			for j, id := range cn.inputIDs {
+				values[j], _ = inputs[i][id].(float64)
+			}
+			result, err := cn.Predict(values)
+			if err != nil {
+				return fmt.Errorf("sample %d: %w", i, err)
+			}
+			outputs[i] = make(map[string]float64, len(result))
+			for j, id := range cn.outputIDs {
+				outputs[i][id] = result[j]
+			}
+			return nil
+		}
+	})
+	return outputs, err
+}
+
+// FeedforwardMatrix runs a matrix of samples whose columns are named by columns through the network.
+// It returns the output IDs in natural order and one row of outputs per sample in that column order.
+// Input neurons without a column are treated as zero, like missing inputs in Feedforward.
+func (bp *Blueprint) FeedforwardMatrix(columns []string, rows [][]float64, workers int) ([]string, [][]float64, error) {
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return nil, nil, fmt.Errorf("row %d has %d values but %d columns were declared", i, len(row), len(columns))
+		}
+	}
+
+	compiled, err := bp.compileDense()
+	if err != nil {
+		inputs := make([]map[string]interface{}, len(rows))
+		for i, row := range rows {
+			inputs[i] = make(map[string]interface{}, len(columns))
+			for j, column := range columns {
+				inputs[i][column] = row[j]
+			}
+		}
+		outputs, err := bp.FeedforwardBatch(inputs, workers)
+		if err != nil {
+			return nil, nil, err
+		}
+		var outputIDs []string
+		if len(outputs) > 0 {
+			outputIDs = sortedKeys(outputs[0])
+		}
+		results := make([][]float64, len(outputs))
+		for i, output := range outputs {
+			results[i] = make([]float64, len(outputIDs))
+			for j, id := range outputIDs {
+				results[i][j] = output[id]
+			}
+		}
+		return outputIDs, results, nil
+	}
+
+	// Map each compiled input position to the declared column that feeds it, or -1 when none does
+	columnIndex := make(map[string]int, len(columns))
+	for j, column := range columns {
+		columnIndex[column] = j
+	}
+	sources := make([]int, len(compiled.inputIDs))
+	for i, id := range compiled.inputIDs {
+		j, ok := columnIndex[id]
+		if !ok {
+			j = -1
+		}
+		sources[i] = j
+	}
+
+	results := make([][]float64, len(rows))
+	err = runBatch(len(rows), workers, func() func(int) error {
+		cn := compiled.Clone()
+		values := make([]float64, len(cn.inputIDs))
+		return func(i int) error {
+			for j, source := range sources {
+				if source >= 0 {
+					values[j] = rows[i][source]
+				}
+			}
+			result, err := cn.Predict(values)
+			if err != nil {
+				return fmt.Errorf("row %d: %w", i, err)
+			}
+			results[i] = append([]float64(nil), result...)
+			return nil
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return compiled.outputIDs, results, nil
+}
+
+// runBatch processes n items across the requested number of workers. newWorker is called once per
+// worker to set up its buffers and returns the function that processes a single item. The error of the
+// lowest failing item is returned.
+func runBatch(n, workers int, newWorker func() func(int) error) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, n)
+	if workers <= 1 {
+		process := newWorker()
+		for i := 0; i < n; i++ {
+			if err := process(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	errs := make([]error, n)
+	chunkSize := (n + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += chunkSize {
+		end := min(start+chunkSize, n)
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			process := newWorker()
+			for i := start; i < end; i++ {
+				if errs[i] = process(i); errs[i] != nil {
+					return
+				}
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package blueprint
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// assertBatchMatchesFeedforward checks FeedforwardBatch against Feedforward for several worker counts.
+func assertBatchMatchesFeedforward(t *testing.T, bp *Blueprint, inputs []map[string]interface{}) {
+	t.Helper()
+	for _, workers := range []int{1, 2, 3, 0} {
+		outputs, err := bp.FeedforwardBatch(inputs, workers)
+		if err != nil {
+			t.Fatalf("%d workers: %v", workers, err)
+		}
+		for i, input := range inputs {
+			want := bp.Feedforward(input)
+			if len(outputs[i]) != len(want) {
+				t.Fatalf("%d workers, sample %d: got %v, want %v", workers, i, outputs[i], want)
+			}
+			for outputID, value := range want {
+				if math.Abs(outputs[i][outputID]-value) > 1e-12 {
+					t.Errorf("%d workers, sample %d, %s: %v, want %v", workers, i, outputID, outputs[i][outputID], value)
+				}
+			}
+		}
+	}
+}
+
+// newRecurrentDenseNetwork returns a dense-input network with an LSTM hidden layer, which FeedforwardBatch
+// cannot compile.
+func newRecurrentDenseNetwork(t *testing.T) *Blueprint {
+	bp := newTestNetwork(21)
+	bp.AppendLSTMLayerWithCells(2)
+	if err := bp.AppendNewLayerFullConnections(3); err != nil {
+		t.Fatal(err)
+	}
+	if err := bp.ReconnectOutputLayer(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bp.compileDense(); err == nil {
+		t.Fatal("network with an LSTM layer compiled")
+	}
+	return bp
+}
+
+func denseSamples(n int) []map[string]interface{} {
+	inputs := make([]map[string]interface{}, n)
+	for i := range inputs {
+		inputs[i] = map[string]interface{}{"neuron0": float64(i) / 3, "neuron1": 1 - float64(i)/5}
+	}
+	return inputs
+}
+
+func TestFeedforwardBatchMatchesFeedforward(t *testing.T) {
+	t.Run("compiled", func(t *testing.T) {
+		assertBatchMatchesFeedforward(t, newTestNetwork(20), denseSamples(7))
+	})
+	t.Run("lstm fallback", func(t *testing.T) {
+		assertBatchMatchesFeedforward(t, newRecurrentDenseNetwork(t), denseSamples(7))
+	})
+	t.Run("conv fallback", func(t *testing.T) {
+		bp, input := newConvTestNetwork(t, 1, 1, "tanh")
+		assertBatchMatchesFeedforward(t, bp, []map[string]interface{}{input, input, input})
+	})
+	t.Run("sequence fallback", func(t *testing.T) {
+		bp, input := newLSTMTestNetwork()
+		assertBatchMatchesFeedforward(t, bp, []map[string]interface{}{input, input, input})
+	})
+}
+
+func TestFeedforwardBatchRejectsNonFloatInputs(t *testing.T) {
+	bp := newTestNetwork(20)
+	inputs := denseSamples(3)
+	inputs[1]["label"] = "cat"
+	_, err := bp.FeedforwardBatch(inputs, 2)
+	var typeErr *InputTypeError
+	if !errors.As(err, &typeErr) || typeErr.Key != "label" {
+		t.Fatalf("got error %v, want an InputTypeError for label", err)
+	}
+	if bp.Feedforward(inputs[1]) != nil {
+		t.Fatal("Feedforward accepted the sample FeedforwardBatch rejects")
+	}
+}
+
+func TestFeedforwardMatrixMatchesFeedforward(t *testing.T) {
+	columns := []string{"neuron1", "neuron0"}
+	rows := [][]float64{{0.2, -0.4}, {1, 0.5}, {-0.3, 0.9}, {0, 0}, {0.7, 0.1}}
+	for name, bp := range map[string]*Blueprint{"compiled": newTestNetwork(22), "fallback": newRecurrentDenseNetwork(t)} {
+		for _, workers := range []int{1, 2, 4} {
+			outputIDs, results, err := bp.FeedforwardMatrix(columns, rows, workers)
+			if err != nil {
+				t.Fatalf("%s, %d workers: %v", name, workers, err)
+			}
+			for i, row := range rows {
+				want := bp.Feedforward(map[string]interface{}{"neuron1": row[0], "neuron0": row[1]})
+				for j, outputID := range outputIDs {
+					if math.Abs(results[i][j]-want[outputID]) > 1e-12 {
+						t.Errorf("%s, %d workers, row %d, %s: %v, want %v", name, workers, i, outputID, results[i][j], want[outputID])
+					}
+				}
+			}
+		}
+	}
+}
@@ -2,6 +2,8 @@ package blueprint
 
 import (
 	"fmt"
+	"math"
+	"math/rand"
 	"runtime"
 	"sync"
 	"time"
@@ -124,3 +126,67 @@ func (bp *Blueprint) FormatNumber(num int) string {
 		return fmt.Sprintf("%d", num)
 	}
 }
+
+// BenchmarkInference runs the same random samples through Feedforward and through the compiled network,
+// returning the average time per sample of each path and the speedup of the compiled one. It fails if
+// the two paths disagree on any output.
+func (bp *Blueprint) BenchmarkInference(numSamples int) (time.Duration, time.Duration, float64, error) {
+	if numSamples <= 0 {
+		return 0, 0, 0, fmt.Errorf("number of samples must be positive")
+	}
+	compiled, err := bp.compileDense()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	// Use a fixed local source so benchmarking does not advance the model's random source
+	rng := rand.New(rand.NewSource(1))
+	samples := make([]map[string]interface{}, numSamples)
+	for i := range samples {
+		samples[i] = make(map[string]interface{}, len(compiled.InputIDs()))
+		for _, id := range compiled.InputIDs() {
+			samples[i][id] = rng.Float64()
+		}
+	}
+
+	expected := make([]map[string]float64, numSamples)
+	startTime := time.Now()
+	for i, sample := range samples {
+		expected[i] = bp.Feedforward(sample)
+	}
+	feedforwardTime := time.Since(startTime) / time.Duration(numSamples)
+
+	inputs := make([][]float64, numSamples)
+	for i, sample := range samples {
+		inputs[i] = make([]float64, len(compiled.InputIDs()))
+		for j, id := range compiled.InputIDs() {
+			inputs[i][j] = sample[id].(float64)
+		}
+	}
+
+	mismatch := ""
+	startTime = time.Now()
+	for i, input := range inputs {
+		outputs, err := compiled.Predict(input)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		for j, id := range compiled.OutputIDs() {
+			want := expected[i][id]
+			if math.Abs(outputs[j]-want) > 1e-9*math.Max(1, math.Abs(want)) && mismatch == "" {
+				mismatch = fmt.Sprintf("sample %d output %s: compiled %v, feedforward %v", i, id, outputs[j], want)
+			}
+		}
+	}
+	compiledTime := time.Since(startTime) / time.Duration(numSamples)
+
+	if mismatch != "" {
+		return 0, 0, 0, fmt.Errorf("compiled network disagrees with Feedforward: %s", mismatch)
+	}
+
+	speedup := math.Inf(1)
+	if compiledTime > 0 {
+		speedup = float64(feedforwardTime) / float64(compiledTime)
+	}
+	return feedforwardTime, compiledTime, speedup, nil
+}
@@ -1,12 +1,20 @@
 package blueprint
 
 import (
+	"fmt"
 	"math"
+	"math/rand"
+	"time"
 )
 
 // Blueprint is the main struct containing the model and related functions.
 type Blueprint struct {
 	Config *NetworkConfig
+
+	// BPTTWindow limits how many trailing time steps LSTM gradients flow through; 0 uses the whole sequence.
+	BPTTWindow int
+
+	rng *rand.Rand
 }
 
 // NewBlueprint creates a new instance of Blueprint with a given configuration.
@@ -16,9 +24,48 @@ func NewBlueprint(config *NetworkConfig) *Blueprint {
 	}
 }
 
+// NewBlueprintWithSeed creates a Blueprint whose random weights, activation choices and mutations are
+// drawn from a source seeded with seed, so the same sequence of calls always produces the same network.
+func NewBlueprintWithSeed(config *NetworkConfig, seed int64) *Blueprint {
+	bp := NewBlueprint(config)
+	bp.SetSeed(seed)
+	return bp
+}
+
+// SetSeed reseeds the Blueprint's random source and records the seed in the model metadata, if the
+// Blueprint has a config.
+func (bp *Blueprint) SetSeed(seed int64) {
+	bp.rng = rand.New(rand.NewSource(seed))
+	if bp.Config != nil {
+		bp.Config.Metadata.Seed = seed
+		bp.Config.Metadata.Seeded = true
+	}
+}
+
+// SetRandSource injects a custom random source. The seed recorded in the metadata is left unchanged.
+func (bp *Blueprint) SetRandSource(src rand.Source) {
+	bp.rng = rand.New(src)
+}
+
+// Rand returns the random source used by every generator and mutation of the Blueprint. If none has been
+// set, it is seeded from Metadata.Seed, or from the current time when no seed is recorded yet, in which
+// case the chosen seed is written to the metadata so the run can be replayed.
+// The returned source is not safe for concurrent use.
+func (bp *Blueprint) Rand() *rand.Rand {
+	if bp.rng == nil {
+		if bp.Config != nil && bp.Config.Metadata.hasSeed() {
+			bp.SetSeed(bp.Config.Metadata.Seed)
+		} else {
+			bp.SetSeed(time.Now().UnixNano())
+		}
+	}
+	return bp.rng
+}
+
 // Connection represents a connection between two neurons with a weight.
 type Connection struct {
-	Weight float64 `json:"weight"`
+	Weight     float64 `json:"weight"`
+	Innovation int64   `json:"innovation,omitempty"` // Historical marking assigned by an InnovationTracker
 }
 
 // Neuron represents a neuron with an activation function, connections, and a bias.
@@ -51,6 +98,17 @@ type Layer struct {
 	Stride    int               `json:"stride,omitempty"`
 	Padding   int               `json:"padding,omitempty"`
 	LSTMCells []LSTMCell        `json:"lstmCells,omitempty"`
+	Shape     []int             `json:"shape,omitempty"` // For conv and lstm input layers: [height, width] or [steps, features]
+
+	ActivationType string `json:"activationType,omitempty"` // For convolutional layers; "relu" when empty
+}
+
+// convActivation returns the activation applied to every feature map value of a convolutional layer.
+func (layer Layer) convActivation() string {
+	if layer.ActivationType == "" {
+		return "relu"
+	}
+	return layer.ActivationType
 }
 
 // ModelMetadata holds metadata for the model.
@@ -72,6 +130,17 @@ type ModelMetadata struct {
 	// New fields for neuron and layer counts using int64 for large values
 	TotalNeurons int64 `json:"totalNeurons"`
 	TotalLayers  int64 `json:"totalLayers"`
+
+	// Seed of the random source that generated and mutated the model. Seeded marks Seed as recorded, so a
+	// seed of 0 can be told apart from none; a non-zero Seed without Seeded, as written by older versions,
+	// also counts as recorded.
+	Seed   int64 `json:"seed"`
+	Seeded bool  `json:"seeded,omitempty"`
+}
+
+// hasSeed reports whether the metadata records the seed of the model's random source.
+func (m ModelMetadata) hasSeed() bool {
+	return m.Seeded || m.Seed != 0
 }
 
 // NetworkConfig represents the structure of the neural network, containing input, hidden, and output layers, and model metadata.
@@ -84,6 +153,12 @@ type NetworkConfig struct {
 	} `json:"layers"`
 }
 
+// SupportedActivations lists the activation types understood by Activate. An empty activation type is
+// treated as linear.
+var SupportedActivations = []string{
+	"relu", "sigmoid", "tanh", "softmax", "log_softmax", "leaky_relu", "swish", "elu", "selu", "softplus", "linear",
+}
+
 // Activate calculates the activation value based on the activation type.
 func (bp *Blueprint) Activate(activationType string, input float64) float64 {
 	switch activationType {
@@ -94,7 +169,9 @@ func (bp *Blueprint) Activate(activationType string, input float64) float64 {
 	case "tanh":
 		return math.Tanh(input)
 	case "softmax":
-		return math.Exp(input) // Should normalize later in the layer processing
+		return math.Exp(input) // Unnormalized; dense layers normalize softmax neurons together
+	case "log_softmax":
+		return input // Unnormalized; dense layers normalize log_softmax neurons together
 	case "leaky_relu":
 		if input > 0 {
 			return input
@@ -122,54 +199,155 @@ func (bp *Blueprint) Activate(activationType string, input float64) float64 {
 	}
 }
 
+// ActivationDerivative returns the derivative of the activation function with respect to its input.
+// Softmax and log_softmax are not elementwise: each output depends on every neuron normalized with it,
+// so dense layers backpropagate them through the full Jacobian (see activationGradients) and they fall
+// through to the linear case here.
+func (bp *Blueprint) ActivationDerivative(activationType string, input float64) float64 {
+	switch activationType {
+	case "relu":
+		if input > 0 {
+			return 1
+		}
+		return 0
+	case "sigmoid":
+		s := bp.sigmoid(input)
+		return s * (1 - s)
+	case "tanh":
+		t := math.Tanh(input)
+		return 1 - t*t
+	case "leaky_relu":
+		if input > 0 {
+			return 1
+		}
+		return 0.01
+	case "swish":
+		s := bp.sigmoid(input)
+		return s + input*s*(1-s)
+	case "elu":
+		alpha := 1.0
+		if input >= 0 {
+			return 1
+		}
+		return alpha * math.Exp(input)
+	case "selu":
+		lambda := 1.0507
+		alphaSELU := 1.6733
+		if input >= 0 {
+			return lambda
+		}
+		return lambda * alphaSELU * math.Exp(input)
+	case "softplus":
+		return bp.sigmoid(input)
+	default:
+		return 1 // Linear activation
+	}
+}
+
 // Feedforward processes the input values through the network and returns the output values.
+// Missing dense inputs are treated as zero. It returns nil if the network cannot process the inputs; use
+// FeedforwardWithError to find out why.
 func (bp *Blueprint) Feedforward(inputValues map[string]interface{}) map[string]float64 {
+	outputs, err := bp.feedforward(inputValues, false)
+	if err != nil {
+		return nil
+	}
+	return outputs
+}
+
+// FeedforwardWithError processes the input values through the network like Feedforward, returning a
+// typed error such as MissingInputError, InputTypeError or a LayerError when the network fails. Unlike
+// Feedforward it requires a value for every dense input neuron.
+func (bp *Blueprint) FeedforwardWithError(inputValues map[string]interface{}) (map[string]float64, error) {
+	return bp.feedforward(inputValues, true)
+}
+
+// feedforward runs the network, returning a MissingInputError for absent dense inputs when requireAll is
+// set and treating them as zero otherwise.
+func (bp *Blueprint) feedforward(inputValues map[string]interface{}, requireAll bool) (map[string]float64, error) {
 	// Load input values into the data variable
-	var data interface{}
+	data, err := bp.loadInputs(inputValues, requireAll)
+	if err != nil {
+		return nil, err
+	}
+
+	// Process hidden layers followed by the output layer
+	for i, layer := range bp.trainableLayers() {
+		data, err = bp.ProcessLayerWithError(*layer, data)
+		if err != nil {
+			return nil, &LayerError{LayerIndex: i, LayerType: layer.LayerType, Err: err}
+		}
+	}
+
+	// Return output values
+	outputData, ok := data.(map[string]float64)
+	if !ok {
+		outputLayer := bp.Config.Layers.Output
+		return nil, &LayerError{
+			LayerIndex: len(bp.Config.Layers.Hidden),
+			LayerType:  outputLayer.LayerType,
+			Err:        shapeMismatch("map[string]float64", data),
+		}
+	}
+	return outputData, nil
+}
 
-	switch bp.Config.Layers.Input.LayerType {
+// loadInputs converts raw input values into the data format expected by the first layer. Dense inputs
+// missing from inputValues are an error when requireAll is set and read as zero by the layers otherwise.
+func (bp *Blueprint) loadInputs(inputValues map[string]interface{}, requireAll bool) (interface{}, error) {
+	inputLayer := bp.Config.Layers.Input
+	switch inputLayer.LayerType {
 	case "dense":
 		inputData := make(map[string]float64)
 		for k, v := range inputValues {
-			if val, ok := v.(float64); ok {
-				inputData[k] = val
-			} else {
-				return nil
+			val, ok := v.(float64)
+			if !ok {
+				return nil, &InputTypeError{Key: k, Expected: "float64", Actual: fmt.Sprintf("%T", v)}
 			}
+			inputData[k] = val
 		}
-		data = inputData
-	case "conv":
-		if imageData, ok := inputValues["image"].([][]float64); ok {
-			data = imageData
-		} else {
-			return nil
+		if requireAll {
+			for _, neuronID := range sortedKeys(inputLayer.Neurons) {
+				if _, ok := inputData[neuronID]; !ok {
+					return nil, &MissingInputError{Key: neuronID}
+				}
+			}
 		}
+		return inputData, nil
+	case "conv":
+		return loadInput[[][]float64](inputValues, "image")
 	case "lstm":
-		if sequenceData, ok := inputValues["sequence"].([][]float64); ok {
-			data = sequenceData
-		} else {
-			return nil
-		}
+		return loadInput[[][]float64](inputValues, "sequence")
+	default:
+		return nil, &LayerError{LayerIndex: -1, LayerType: inputLayer.LayerType, Err: &UnknownLayerTypeError{LayerType: inputLayer.LayerType}}
 	}
+}
 
-	// Process hidden layers
-	for _, layer := range bp.Config.Layers.Hidden {
-		data = bp.ProcessLayer(layer, data)
+// loadInput fetches a required input value of type T.
+func loadInput[T any](inputValues map[string]interface{}, key string) (interface{}, error) {
+	raw, ok := inputValues[key]
+	if !ok {
+		return nil, &MissingInputError{Key: key}
 	}
-
-	// Process output layer
-	outputLayer := bp.Config.Layers.Output
-	data = bp.ProcessLayer(outputLayer, data)
-
-	// Return output values
-	if outputData, ok := data.(map[string]float64); ok {
-		return outputData
+	value, ok := raw.(T)
+	if !ok {
+		return nil, &InputTypeError{Key: key, Expected: fmt.Sprintf("%T", *new(T)), Actual: fmt.Sprintf("%T", raw)}
 	}
-	return nil
+	return value, nil
 }
 
 // ProcessLayer handles processing of each layer type within the network
 func (bp *Blueprint) ProcessLayer(layer Layer, inputData interface{}) interface{} {
+	output, err := bp.ProcessLayerWithError(layer, inputData)
+	if err != nil {
+		return nil
+	}
+	return output
+}
+
+// ProcessLayerWithError processes a single layer, returning a ShapeMismatchError when the input data does
+// not suit the layer or an UnknownLayerTypeError for unsupported layer types.
+func (bp *Blueprint) ProcessLayerWithError(layer Layer, inputData interface{}) (interface{}, error) {
 	switch layer.LayerType {
 	case "dense":
 		return bp.processDenseLayer(layer, inputData)
@@ -178,7 +356,7 @@ func (bp *Blueprint) ProcessLayer(layer Layer, inputData interface{}) interface{
 	case "lstm":
 		return bp.processLSTMLayer(layer, inputData)
 	default:
-		return nil
+		return nil, &UnknownLayerTypeError{LayerType: layer.LayerType}
 	}
 }
 
@@ -0,0 +1,76 @@
+package blueprint
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// newPartialInputNetwork returns a small dense network with the input for neuron1 left out, and the
+// same input with neuron1 explicitly set to zero.
+func newPartialInputNetwork() (*Blueprint, map[string]interface{}, map[string]interface{}) {
+	bp := NewBlueprintWithSeed(&NetworkConfig{}, 12)
+	bp.CreateCustomNetworkConfig(3, 4, 2, []string{"sigmoid", "tanh"}, "test", "test", XavierInitializer{})
+	partial := map[string]interface{}{"neuron0": 0.5, "neuron2": -0.25}
+	zeroFilled := map[string]interface{}{"neuron0": 0.5, "neuron1": 0.0, "neuron2": -0.25}
+	return bp, partial, zeroFilled
+}
+
+func TestFeedforwardTreatsMissingInputsAsZero(t *testing.T) {
+	bp, partial, zeroFilled := newPartialInputNetwork()
+	got, want := bp.Feedforward(partial), bp.Feedforward(zeroFilled)
+	if got == nil || len(got) != len(want) {
+		t.Fatalf("Feedforward with a missing input returned %v", got)
+	}
+	for outputID, value := range want {
+		if math.Abs(got[outputID]-value) > 1e-12 {
+			t.Errorf("output %s: %v, want %v", outputID, got[outputID], value)
+		}
+	}
+
+	outputs, err := bp.FeedforwardBatch([]map[string]interface{}{partial, zeroFilled}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for outputID, value := range want {
+		if math.Abs(outputs[0][outputID]-value) > 1e-12 {
+			t.Errorf("batch output %s: %v, want %v", outputID, outputs[0][outputID], value)
+		}
+	}
+
+	outputIDs, rows, err := bp.FeedforwardMatrix([]string{"neuron2", "neuron0"}, [][]float64{{-0.25, 0.5}}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for j, outputID := range outputIDs {
+		if math.Abs(rows[0][j]-want[outputID]) > 1e-12 {
+			t.Errorf("matrix output %s: %v, want %v", outputID, rows[0][j], want[outputID])
+		}
+	}
+}
+
+func TestFeedforwardWithErrorTypedErrors(t *testing.T) {
+	bp, partial, _ := newPartialInputNetwork()
+
+	var missing *MissingInputError
+	if _, err := bp.FeedforwardWithError(partial); !errors.As(err, &missing) || missing.Key != "neuron1" {
+		t.Fatalf("got %v, want a MissingInputError for neuron1", err)
+	}
+
+	var inputType *InputTypeError
+	badType := map[string]interface{}{"neuron0": 0.5, "neuron1": "high", "neuron2": -0.25}
+	if _, err := bp.FeedforwardWithError(badType); !errors.As(err, &inputType) || inputType.Key != "neuron1" {
+		t.Fatalf("got %v, want an InputTypeError for neuron1", err)
+	}
+	if bp.Feedforward(badType) != nil {
+		t.Fatal("Feedforward accepted an input of the wrong type")
+	}
+
+	bp.Config.Layers.Hidden = append(bp.Config.Layers.Hidden, Layer{LayerType: "pooling"})
+	var layerErr *LayerError
+	var unknown *UnknownLayerTypeError
+	_, err := bp.FeedforwardWithError(map[string]interface{}{"neuron0": 0.5, "neuron1": 0.1, "neuron2": -0.25})
+	if !errors.As(err, &layerErr) || layerErr.LayerIndex != 1 || !errors.As(err, &unknown) {
+		t.Fatalf("got %v, want an UnknownLayerTypeError in layer 1", err)
+	}
+}
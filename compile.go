@@ -0,0 +1,303 @@
+// blueprint/compile.go
+package blueprint
+
+import "fmt"
+
+// CompiledNetwork is an index-based form of a NetworkConfig of dense and convolutional layers. Neurons are
+// kept in a fixed natural order and weights are stored as contiguous row-major matrices, so Predict avoids
+// the map lookups and allocations of Feedforward while producing the same outputs.
+// A CompiledNetwork reuses its buffers between calls and must not be shared between goroutines; use
+// Clone to get an independent copy that shares the weights.
+type CompiledNetwork struct {
+	bp        *Blueprint
+	inputIDs  []string
+	inputSize int // Number of input values; the flattened image size for conv input layers
+	outputIDs []string
+	layers    []*compiledLayer
+	buffers   [][]float64 // buffers[0] holds the inputs, buffers[i+1] the outputs of layers[i]
+}
+
+// compiledLayer holds the weights and activations of one dense or convolutional layer in index form.
+type compiledLayer struct {
+	numInputs     int
+	weights       []float64 // len(biases) rows of numInputs columns
+	biases        []float64
+	activations   []string
+	softmaxIdx    []int
+	logSoftmaxIdx []int
+	groupBuffer   []float64
+	conv          *compiledConv // Set for convolutional layers, which leave the dense fields empty
+}
+
+// compiledConv holds the filters of one convolutional layer and the dimensions of the images it reads.
+type compiledConv struct {
+	activation              string
+	stride, padding         int
+	channels, height, width int
+	filters                 []compiledFilter
+	numOutputs              int
+}
+
+// compiledFilter holds one convolution kernel in row-major order and the size of its feature maps.
+type compiledFilter struct {
+	weights             []float64
+	rows, cols          int
+	bias                float64
+	outHeight, outWidth int
+}
+
+// Compile converts the network into a CompiledNetwork. Dense and convolutional layers are supported; a
+// conv input layer must declare its dimensions in Layer.Shape. Connections to IDs that the previous layer
+// does not produce contribute nothing, just as a missing map entry would in Feedforward.
+func (bp *Blueprint) Compile() (*CompiledNetwork, error) {
+	shapes, err := bp.InferShapes()
+	if err != nil {
+		return nil, fmt.Errorf("cannot compile: %w", err)
+	}
+
+	cn := &CompiledNetwork{bp: bp}
+	switch input := shapes[0]; input.Kind {
+	case "vector":
+		cn.inputIDs, cn.inputSize = input.Keys, len(input.Keys)
+	case "image":
+		cn.inputSize = input.Size()
+	default:
+		return nil, fmt.Errorf("cannot compile %q input layer: only dense and conv inputs are supported", bp.Config.Layers.Input.LayerType)
+	}
+
+	for i, layer := range bp.trainableLayers() {
+		switch layer.LayerType {
+		case "dense":
+			cn.layers = append(cn.layers, compileDenseLayer(*layer, shapes[i].Keys))
+		case "conv":
+			cn.layers = append(cn.layers, compileConvLayer(*layer, shapes[i]))
+		default:
+			return nil, fmt.Errorf("cannot compile layer %d: %q layers are not supported", i, layer.LayerType)
+		}
+	}
+	cn.outputIDs = shapes[len(shapes)-1].Keys
+
+	cn.allocateBuffers()
+	return cn, nil
+}
+
+// compileDense compiles the network like Compile but fails unless the input layer is dense, for callers
+// that feed named input values.
+func (bp *Blueprint) compileDense() (*CompiledNetwork, error) {
+	if bp.Config.Layers.Input.LayerType != "dense" {
+		return nil, fmt.Errorf("cannot compile %q input layer: named inputs need a dense input layer", bp.Config.Layers.Input.LayerType)
+	}
+	return bp.Compile()
+}
+
+// compileDenseLayer converts a dense layer reading the values named by previousIDs into index form.
+func compileDenseLayer(layer Layer, previousIDs []string) *compiledLayer {
+	previousIndex := make(map[string]int, len(previousIDs))
+	for idx, id := range previousIDs {
+		previousIndex[id] = idx
+	}
+
+	neuronIDs := sortedKeys(layer.Neurons)
+	cl := &compiledLayer{
+		numInputs:   len(previousIDs),
+		weights:     make([]float64, len(neuronIDs)*len(previousIDs)),
+		biases:      make([]float64, len(neuronIDs)),
+		activations: make([]string, len(neuronIDs)),
+	}
+	for row, neuronID := range neuronIDs {
+		neuron := layer.Neurons[neuronID]
+		for inputID, conn := range neuron.Connections {
+			if col, ok := previousIndex[inputID]; ok {
+				cl.weights[row*cl.numInputs+col] = conn.Weight
+			}
+		}
+		cl.biases[row] = neuron.Bias
+		cl.activations[row] = neuron.ActivationType
+		switch neuron.ActivationType {
+		case "softmax":
+			cl.softmaxIdx = append(cl.softmaxIdx, row)
+		case "log_softmax":
+			cl.logSoftmaxIdx = append(cl.logSoftmaxIdx, row)
+		}
+	}
+	cl.groupBuffer = make([]float64, max(len(cl.softmaxIdx), len(cl.logSoftmaxIdx)))
+	return cl
+}
+
+// compileConvLayer converts a convolutional layer reading images of the given shape into index form.
+// InferShapes has already checked that every filter fits the images.
+func compileConvLayer(layer Layer, in LayerShape) *compiledLayer {
+	cc := &compiledConv{
+		activation: layer.convActivation(),
+		stride:     layer.Stride,
+		padding:    layer.Padding,
+		channels:   in.Channels,
+		height:     in.Height,
+		width:      in.Width,
+	}
+	for _, filter := range layer.Filters {
+		cf := compiledFilter{
+			rows: len(filter.Weights),
+			cols: len(filter.Weights[0]),
+			bias: filter.Bias,
+		}
+		cf.weights = make([]float64, cf.rows*cf.cols)
+		for r, row := range filter.Weights {
+			copy(cf.weights[r*cf.cols:(r+1)*cf.cols], row)
+		}
+		cf.outHeight = (in.Height+2*layer.Padding-cf.rows)/layer.Stride + 1
+		cf.outWidth = (in.Width+2*layer.Padding-cf.cols)/layer.Stride + 1
+		cc.filters = append(cc.filters, cf)
+		cc.numOutputs += in.Channels * cf.outHeight * cf.outWidth
+	}
+	return &compiledLayer{conv: cc}
+}
+
+// numOutputs returns the number of values the layer produces.
+func (cl *compiledLayer) numOutputs() int {
+	if cl.conv != nil {
+		return cl.conv.numOutputs
+	}
+	return len(cl.biases)
+}
+
+// Clone returns a CompiledNetwork that shares the weights of cn but has its own buffers, so it can be
+// used from another goroutine.
+func (cn *CompiledNetwork) Clone() *CompiledNetwork {
+	clone := &CompiledNetwork{
+		bp:        cn.bp,
+		inputIDs:  cn.inputIDs,
+		inputSize: cn.inputSize,
+		outputIDs: cn.outputIDs,
+		layers:    make([]*compiledLayer, len(cn.layers)),
+	}
+	for i, cl := range cn.layers {
+		layerCopy := *cl
+		layerCopy.groupBuffer = make([]float64, len(cl.groupBuffer))
+		clone.layers[i] = &layerCopy
+	}
+	clone.allocateBuffers()
+	return clone
+}
+
+// allocateBuffers creates the activation buffers reused by every Predict call.
+func (cn *CompiledNetwork) allocateBuffers() {
+	cn.buffers = make([][]float64, len(cn.layers)+1)
+	cn.buffers[0] = make([]float64, cn.inputSize)
+	for i, cl := range cn.layers {
+		cn.buffers[i+1] = make([]float64, cl.numOutputs())
+	}
+}
+
+// InputIDs returns the input neuron IDs in the order Predict expects them, or nil for a conv input layer.
+func (cn *CompiledNetwork) InputIDs() []string {
+	return cn.inputIDs
+}
+
+// OutputIDs returns the output neuron IDs in the order Predict returns them.
+func (cn *CompiledNetwork) OutputIDs() []string {
+	return cn.outputIDs
+}
+
+// Predict runs the network on inputs ordered like InputIDs and returns the outputs ordered like OutputIDs.
+// A conv input layer takes its image flattened row by row instead. The returned slice is owned by the
+// CompiledNetwork and is overwritten by the next call.
+func (cn *CompiledNetwork) Predict(inputs []float64) ([]float64, error) {
+	if len(inputs) != cn.inputSize {
+		return nil, fmt.Errorf("expected %d inputs, got %d", cn.inputSize, len(inputs))
+	}
+	copy(cn.buffers[0], inputs)
+
+	for i, cl := range cn.layers {
+		cn.forwardLayer(cl, cn.buffers[i], cn.buffers[i+1])
+	}
+	return cn.buffers[len(cn.layers)], nil
+}
+
+// PredictMap runs the network on named inputs like Feedforward. Missing inputs are treated as zero, as is
+// the whole image of a conv input layer, which has no named inputs; use Predict for those networks.
+func (cn *CompiledNetwork) PredictMap(inputValues map[string]float64) map[string]float64 {
+	for i, id := range cn.inputIDs {
+		cn.buffers[0][i] = inputValues[id]
+	}
+	for i, cl := range cn.layers {
+		cn.forwardLayer(cl, cn.buffers[i], cn.buffers[i+1])
+	}
+
+	outputs := cn.buffers[len(cn.layers)]
+	result := make(map[string]float64, len(outputs))
+	for i, id := range cn.outputIDs {
+		result[id] = outputs[i]
+	}
+	return result
+}
+
+// forwardLayer computes one layer from in into out.
+func (cn *CompiledNetwork) forwardLayer(cl *compiledLayer, in, out []float64) {
+	if cl.conv != nil {
+		cn.forwardConv(cl.conv, in, out)
+		return
+	}
+
+	for row := range cl.biases {
+		weights := cl.weights[row*cl.numInputs : (row+1)*cl.numInputs]
+		sum := 0.0
+		for col, w := range weights {
+			sum += in[col] * w
+		}
+		out[row] = sum + cl.biases[row]
+	}
+
+	normalizeGroup := func(indices []int, normalize func([]float64)) {
+		if len(indices) == 0 {
+			return
+		}
+		values := cl.groupBuffer[:len(indices)]
+		for i, row := range indices {
+			values[i] = out[row]
+		}
+		normalize(values)
+		for i, row := range indices {
+			out[row] = values[i]
+		}
+	}
+
+	for row, activationType := range cl.activations {
+		if activationType != "softmax" && activationType != "log_softmax" {
+			out[row] = cn.bp.Activate(activationType, out[row])
+		}
+	}
+	normalizeGroup(cl.softmaxIdx, softmaxInPlace)
+	normalizeGroup(cl.logSoftmaxIdx, logSoftmaxInPlace)
+}
+
+// forwardConv convolves every input channel with every filter, writing the activated feature maps in the
+// order convForward flattens them. Padding is implicit: positions outside the image are skipped.
+func (cn *CompiledNetwork) forwardConv(cc *compiledConv, in, out []float64) {
+	idx := 0
+	for _, filter := range cc.filters {
+		for c := 0; c < cc.channels; c++ {
+			image := in[c*cc.height*cc.width : (c+1)*cc.height*cc.width]
+			for i := 0; i < filter.outHeight; i++ {
+				for j := 0; j < filter.outWidth; j++ {
+					sum := 0.0
+					for ki := 0; ki < filter.rows; ki++ {
+						r := i*cc.stride + ki - cc.padding
+						if r < 0 || r >= cc.height {
+							continue
+						}
+						for kj := 0; kj < filter.cols; kj++ {
+							col := j*cc.stride + kj - cc.padding
+							if col < 0 || col >= cc.width {
+								continue
+							}
+							sum += image[r*cc.width+col] * filter.weights[ki*filter.cols+kj]
+						}
+					}
+					out[idx] = cn.bp.Activate(cc.activation, sum+filter.bias)
+					idx++
+				}
+			}
+		}
+	}
+}
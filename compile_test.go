@@ -0,0 +1,123 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+// assertPredictMatchesFeedforward checks that the compiled network reproduces Feedforward on input, whose
+// values Predict receives as flat.
+func assertPredictMatchesFeedforward(t *testing.T, bp *Blueprint, input map[string]interface{}, flat []float64) {
+	t.Helper()
+	want, err := bp.FeedforwardWithError(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compiled, err := bp.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := compiled.Predict(flat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("compiled network has %d outputs, Feedforward %d", len(got), len(want))
+	}
+	for i, outputID := range compiled.OutputIDs() {
+		if math.Abs(got[i]-want[outputID]) > 1e-12 {
+			t.Errorf("output %s: compiled %v, Feedforward %v", outputID, got[i], want[outputID])
+		}
+	}
+}
+
+// denseInput returns named and ordered input values for the input neurons of bp.
+func denseInput(bp *Blueprint) (map[string]interface{}, []float64) {
+	ids := sortedKeys(bp.Config.Layers.Input.Neurons)
+	named := make(map[string]interface{}, len(ids))
+	flat := wave(len(ids), 0.2, 1)
+	for i, id := range ids {
+		named[id] = flat[i]
+	}
+	return named, flat
+}
+
+func TestCompiledMatchesFeedforwardDense(t *testing.T) {
+	bp := NewBlueprintWithSeed(&NetworkConfig{}, 9)
+	bp.CreateCustomNetworkConfig(5, 8, 3, []string{"sigmoid", "tanh", "linear"}, "test", "test", XavierInitializer{})
+	bp.AppendMultipleLayers(2, 6)
+	bp.ReconnectOutputLayer()
+	input, flat := denseInput(bp)
+	assertPredictMatchesFeedforward(t, bp, input, flat)
+}
+
+func TestCompiledMatchesFeedforwardSoftmax(t *testing.T) {
+	bp := NewBlueprintWithSeed(&NetworkConfig{}, 10)
+	bp.CreateCustomNetworkConfig(4, 6, 5, nil, "test", "test", NormalInitializer{StdDev: 1})
+	setActivations(&bp.Config.Layers.Hidden[0], "softmax", "tanh")
+	setActivations(&bp.Config.Layers.Output, "softmax", "log_softmax", "softmax", "log_softmax", "sigmoid")
+	input, flat := denseInput(bp)
+	assertPredictMatchesFeedforward(t, bp, input, flat)
+}
+
+func TestCompiledMatchesFeedforwardConv(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		stride, padding int
+		activation      string
+	}{
+		{"relu", 1, 0, "relu"},
+		{"padded", 1, 2, "tanh"},
+		{"strided", 2, 1, "sigmoid"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bp, input := newConvTestNetwork(t, tc.stride, tc.padding, tc.activation)
+			var flat []float64
+			for _, row := range input["image"].([][]float64) {
+				flat = append(flat, row...)
+			}
+			assertPredictMatchesFeedforward(t, bp, input, flat)
+		})
+	}
+}
+
+func TestCompileRejectsLSTM(t *testing.T) {
+	bp, _ := newLSTMTestNetwork()
+	if _, err := bp.Compile(); err == nil {
+		t.Fatal("compiled a network with LSTM layers")
+	}
+}
+
+// newBenchmarkNetwork builds a dense network of 50 inputs, four hidden layers of 100 neurons and 10 outputs.
+func newBenchmarkNetwork() (*Blueprint, map[string]interface{}, []float64) {
+	bp := NewBlueprintWithSeed(&NetworkConfig{}, 11)
+	bp.CreateCustomNetworkConfig(50, 100, 10, nil, "bench", "bench", XavierInitializer{})
+	bp.AppendMultipleLayers(3, 100, XavierInitializer{})
+	bp.ReconnectOutputLayer()
+	input, flat := denseInput(bp)
+	return bp, input, flat
+}
+
+func BenchmarkFeedforward(b *testing.B) {
+	bp, input, _ := newBenchmarkNetwork()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bp.Feedforward(input)
+	}
+}
+
+func BenchmarkCompiled(b *testing.B) {
+	bp, _, flat := newBenchmarkNetwork()
+	compiled, err := bp.Compile()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := compiled.Predict(flat); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -2,35 +2,57 @@ package blueprint
 
 import "fmt"
 
-func (bp *Blueprint) processConvLayer(layer Layer, inputData interface{}) interface{} {
+func (bp *Blueprint) processConvLayer(layer Layer, inputData interface{}) (interface{}, error) {
+	trace, output := bp.convForward(layer, inputData)
+	if trace == nil {
+		return nil, shapeMismatch("[][]float64 or [][][]float64 image", inputData)
+	}
+	return output, nil
+}
+
+// convTrace records the inputs and pre-activation feature maps of a convolutional layer.
+type convTrace struct {
+	inputImages    [][][]float64
+	singleImage    bool
+	preActivations [][][]float64 // One feature map per filter and input image, in flattening order
+}
+
+// convForward convolves every input image with every filter and flattens the activated feature maps
+// into "conv_output%d" keys. It returns a nil trace when the input is not an image.
+func (bp *Blueprint) convForward(layer Layer, inputData interface{}) (*convTrace, map[string]float64) {
 	// inputData is expected to be [][]float64 (2D image) or [][][]float64 (multiple feature maps)
+	trace := &convTrace{}
 	inputImages, ok := inputData.([][][]float64)
 	if !ok {
 		// Try to convert single image to array of images
-		if singleImage, ok := inputData.([][]float64); ok {
-			inputImages = [][][]float64{singleImage}
-		} else {
-			// Handle error
-			return nil
+		singleImage, ok := inputData.([][]float64)
+		if !ok {
+			return nil, nil
 		}
+		inputImages = [][][]float64{singleImage}
+		trace.singleImage = true
 	}
+	trace.inputImages = inputImages
 
 	outputFeatureMaps := [][][]float64{}
+	activation := layer.convActivation()
 
 	for _, filter := range layer.Filters {
-		featureMapsForFilter := [][][]float64{}
 		for _, inputImage := range inputImages {
 			featureMap := bp.convolve(inputImage, filter.Weights, layer.Stride, layer.Padding)
+			preActivation := make([][]float64, len(featureMap))
 			// Apply activation function to each element in featureMap
 			for i := range featureMap {
+				preActivation[i] = make([]float64, len(featureMap[i]))
 				for j := range featureMap[i] {
-					featureMap[i][j] = bp.Activate("relu", featureMap[i][j]+filter.Bias) // Assuming ReLU activation
+					preActivation[i][j] = featureMap[i][j] + filter.Bias
+					featureMap[i][j] = bp.Activate(activation, preActivation[i][j])
 				}
 			}
-			featureMapsForFilter = append(featureMapsForFilter, featureMap)
+			trace.preActivations = append(trace.preActivations, preActivation)
+			// For simplicity, just append them
+			outputFeatureMaps = append(outputFeatureMaps, featureMap)
 		}
-		// For simplicity, just append them
-		outputFeatureMaps = append(outputFeatureMaps, featureMapsForFilter...)
 	}
 
 	// Flatten outputFeatureMaps into map[string]float64
@@ -46,7 +68,73 @@ func (bp *Blueprint) processConvLayer(layer Layer, inputData interface{}) interf
 		}
 	}
 
-	return flattenedOutput
+	return trace, flattenedOutput
+}
+
+// convBackward unflattens the gradient on the "conv_output%d" keys and backpropagates it through the
+// activation and convolution, returning the filter gradients and the gradient with respect to the input
+// images in the same shape the layer received them.
+func (bp *Blueprint) convBackward(layer Layer, trace *convTrace, gradOut map[string]float64) ([]FilterGradient, interface{}) {
+	filterGrads := make([]FilterGradient, len(layer.Filters))
+	inputGrads := make([][][]float64, len(trace.inputImages))
+	for c, inputImage := range trace.inputImages {
+		inputGrads[c] = make([][]float64, len(inputImage))
+		for i := range inputImage {
+			inputGrads[c][i] = make([]float64, len(inputImage[i]))
+		}
+	}
+
+	idx := 0
+	mapIdx := 0
+	activation := layer.convActivation()
+	for f, filter := range layer.Filters {
+		filterGrad := FilterGradient{Weights: make([][]float64, len(filter.Weights))}
+		for ki := range filter.Weights {
+			filterGrad.Weights[ki] = make([]float64, len(filter.Weights[ki]))
+		}
+
+		for c, inputImage := range trace.inputImages {
+			preActivation := trace.preActivations[mapIdx]
+			mapIdx++
+
+			paddedInput := bp.pad2D(inputImage, layer.Padding)
+			paddedGrad := make([][]float64, len(paddedInput))
+			for i := range paddedGrad {
+				paddedGrad[i] = make([]float64, len(paddedInput[i]))
+			}
+
+			for i := range preActivation {
+				for j := range preActivation[i] {
+					key := fmt.Sprintf("conv_output%d", idx)
+					idx++
+					delta := gradOut[key] * bp.ActivationDerivative(activation, preActivation[i][j])
+					if delta == 0 {
+						continue
+					}
+					filterGrad.Bias += delta
+					for ki := range filter.Weights {
+						for kj := range filter.Weights[ki] {
+							row, col := i*layer.Stride+ki, j*layer.Stride+kj
+							filterGrad.Weights[ki][kj] += delta * paddedInput[row][col]
+							paddedGrad[row][col] += delta * filter.Weights[ki][kj]
+						}
+					}
+				}
+			}
+
+			for i := range inputGrads[c] {
+				for j := range inputGrads[c][i] {
+					inputGrads[c][i][j] += paddedGrad[i+layer.Padding][j+layer.Padding]
+				}
+			}
+		}
+		filterGrads[f] = filterGrad
+	}
+
+	if trace.singleImage {
+		return filterGrads, inputGrads[0]
+	}
+	return filterGrads, inputGrads
 }
 
 func (bp *Blueprint) convolve(input [][]float64, kernel [][]float64, stride int, padding int) [][]float64 {
@@ -0,0 +1,50 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+// newConvTestNetwork builds an image network with one conv layer, a tanh dense layer and two sigmoid
+// outputs, and returns it with a fixed input image.
+func newConvTestNetwork(t *testing.T, stride, padding int, activation string) (*Blueprint, map[string]interface{}) {
+	t.Helper()
+	bp := NewBlueprintWithSeed(&NetworkConfig{}, 6)
+	bp.Config.Layers.Input = Layer{LayerType: "conv", Shape: []int{5, 6}}
+	if err := bp.AppendCNNLayer(3, 2, stride, padding, NormalInitializer{StdDev: 0.5}); err != nil {
+		t.Fatal(err)
+	}
+	bp.Config.Layers.Hidden[0].ActivationType = activation
+	bp.AppendNewLayerFullConnections(3, XavierInitializer{})
+	setActivations(&bp.Config.Layers.Hidden[1], "tanh")
+	bp.ReattachOutputLayerZeroBias(2, []string{"sigmoid", "sigmoid"})
+
+	image := make([][]float64, 5)
+	for i := range image {
+		image[i] = make([]float64, 6)
+		for j := range image[i] {
+			image[i][j] = math.Sin(float64(3*i+j) + 0.5)
+		}
+	}
+	return bp, map[string]interface{}{"image": image}
+}
+
+func TestComputeGradientsConv(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		stride, padding int
+		activation      string
+	}{
+		{"relu", 1, 0, "relu"},
+		{"tanh_padded", 1, 1, "tanh"},
+		{"sigmoid_strided", 2, 1, "sigmoid"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bp, input := newConvTestNetwork(t, tc.stride, tc.padding, tc.activation)
+			if len(bp.Config.Layers.Hidden[0].Filters) != 2 {
+				t.Fatalf("got %d filters, want 2", len(bp.Config.Layers.Hidden[0].Filters))
+			}
+			checkGradients(t, bp, input, targetsFor(t, bp, input, 1, 0), BinaryCrossEntropy{})
+		})
+	}
+}
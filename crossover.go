@@ -0,0 +1,208 @@
+// blueprint/crossover.go
+package blueprint
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Crossover combines bp (the primary parent) with other into a new child Blueprint.
+//
+// Layers are aligned by index, with the output layers aligned to each other. When both parents have a
+// layer of the same type at an index, matching genes are inherited at random from either parent: dense
+// neurons are matched by ID (their connection weights, bias and activation), conv filters and LSTM
+// cells by position when their dimensions agree. Genes only the primary parent has are kept, following
+// the NEAT convention of inheriting disjoint genes from the fitter parent.
+//
+// strategy decides what happens when the layer types differ: "primary" keeps the primary parent's layer
+// and "random" takes either parent's layer with equal chance, provided the other parent's layer fits the
+// data the child produces at that point. Connections left dangling by an adopted layer are removed, and
+// neurons that lose every connection are rewired to the new previous layer.
+//
+// seed seeds the child's random source, which makes every choice above, so the same parents and seed
+// always produce the same child. The child records both parents in ParentModelIDs; the parents and their
+// random sources are not modified.
+func (bp *Blueprint) Crossover(other *Blueprint, strategy string, seed int64) (*Blueprint, error) {
+	if strategy != "primary" && strategy != "random" {
+		return nil, fmt.Errorf("unknown crossover strategy: %s", strategy)
+	}
+	if bp.Config.Layers.Input.LayerType != other.Config.Layers.Input.LayerType {
+		return nil, fmt.Errorf("cannot cross %q input layer with %q input layer", bp.Config.Layers.Input.LayerType, other.Config.Layers.Input.LayerType)
+	}
+
+	child := bp.Clone()
+	child.SetSeed(seed)
+	rng := child.Rand()
+
+	childLayers := child.trainableLayers()
+	otherLayers := other.trainableLayers()
+	shape, err := child.inputShape()
+	known := err == nil
+
+	for i, layer := range childLayers {
+		var otherLayer *Layer
+		switch {
+		case i == len(childLayers)-1:
+			otherLayer = otherLayers[len(otherLayers)-1]
+		case i < len(otherLayers)-1:
+			otherLayer = otherLayers[i]
+		}
+
+		if otherLayer != nil {
+			if otherLayer.LayerType == layer.LayerType {
+				crossLayers(layer, otherLayer, rng)
+			} else if strategy == "random" && rng.Intn(2) == 1 && known && child.canAdoptLayer(i, *otherLayer, shape) {
+				*layer = copyLayer(*otherLayer)
+			}
+		}
+
+		if !known {
+			continue
+		}
+		if layer.LayerType == "dense" && shape.Kind == "vector" {
+			child.reconnectDenseLayer(layer, shape.Keys)
+		}
+		if shape, err = inferLayerShape(*layer, shape); err != nil {
+			known = false
+		}
+	}
+
+	child.UpdateMetadataCounts()
+	metadata := &child.Config.Metadata
+	metadata.ModelID = bp.Config.Metadata.ModelID + "_x_" + other.Config.Metadata.ModelID
+	metadata.ParentModelIDs = []string{bp.Config.Metadata.ModelID, other.Config.Metadata.ModelID}
+	metadata.ChildModelIDs = nil
+	metadata.Evaluated = false
+	return child, nil
+}
+
+// crossLayers replaces genes of layer with the matching genes of other at random. Both layers must have
+// the same type.
+func crossLayers(layer, other *Layer, rng *rand.Rand) {
+	switch layer.LayerType {
+	case "dense":
+		for _, neuronID := range sortedKeys(layer.Neurons) {
+			neuron := layer.Neurons[neuronID]
+			otherNeuron, ok := other.Neurons[neuronID]
+			if !ok {
+				continue
+			}
+			for _, inputID := range sortedKeys(neuron.Connections) {
+				if otherConn, ok := otherNeuron.Connections[inputID]; ok && rng.Intn(2) == 1 {
+					neuron.Connections[inputID] = otherConn
+				}
+			}
+			if rng.Intn(2) == 1 {
+				neuron.Bias = otherNeuron.Bias
+			}
+			if rng.Intn(2) == 1 {
+				neuron.ActivationType = otherNeuron.ActivationType
+			}
+			layer.Neurons[neuronID] = neuron
+		}
+
+	case "conv":
+		for f := range layer.Filters {
+			if f >= len(other.Filters) || !sameKernelShape(layer.Filters[f].Weights, other.Filters[f].Weights) {
+				continue
+			}
+			if rng.Intn(2) == 1 {
+				weights := make([][]float64, len(other.Filters[f].Weights))
+				for r, row := range other.Filters[f].Weights {
+					weights[r] = append([]float64(nil), row...)
+				}
+				layer.Filters[f] = Filter{Weights: weights, Bias: other.Filters[f].Bias}
+			}
+		}
+
+	case "lstm":
+		for c := range layer.LSTMCells {
+			if c >= len(other.LSTMCells) || len(layer.LSTMCells[c].InputWeights) != len(other.LSTMCells[c].InputWeights) {
+				continue
+			}
+			if rng.Intn(2) == 1 {
+				otherCell := other.LSTMCells[c]
+				layer.LSTMCells[c] = LSTMCell{
+					InputWeights:  append([]float64(nil), otherCell.InputWeights...),
+					ForgetWeights: append([]float64(nil), otherCell.ForgetWeights...),
+					OutputWeights: append([]float64(nil), otherCell.OutputWeights...),
+					CellWeights:   append([]float64(nil), otherCell.CellWeights...),
+					Bias:          otherCell.Bias,
+				}
+			}
+		}
+	}
+}
+
+// canAdoptLayer reports whether candidate can replace the trainable layer at index without breaking the
+// shape it receives or reusing a neuron ID from another layer.
+func (bp *Blueprint) canAdoptLayer(index int, candidate Layer, in LayerShape) bool {
+	if _, err := inferLayerShape(candidate, in); err != nil {
+		return false
+	}
+	if candidate.LayerType == "lstm" {
+		for _, cell := range candidate.LSTMCells {
+			if len(cell.InputWeights) != in.featureWidth() {
+				return false
+			}
+		}
+	}
+
+	usedIDs := make(map[string]bool)
+	for id := range bp.Config.Layers.Input.Neurons {
+		usedIDs[id] = true
+	}
+	for i, layer := range bp.trainableLayers() {
+		if i == index {
+			continue
+		}
+		for id := range layer.Neurons {
+			usedIDs[id] = true
+		}
+	}
+	for id := range candidate.Neurons {
+		if usedIDs[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// reconnectDenseLayer removes connections that do not match any of previousKeys and connects neurons
+// left without connections to every previous key with small Xavier-initialized weights.
+func (bp *Blueprint) reconnectDenseLayer(layer *Layer, previousKeys []string) {
+	valid := make(map[string]bool, len(previousKeys))
+	for _, key := range previousKeys {
+		valid[key] = true
+	}
+
+	for _, neuronID := range sortedKeys(layer.Neurons) {
+		neuron := layer.Neurons[neuronID]
+		for inputID := range neuron.Connections {
+			if !valid[inputID] {
+				delete(neuron.Connections, inputID)
+			}
+		}
+		if len(neuron.Connections) == 0 && len(previousKeys) > 0 {
+			neuron.Connections = make(map[string]Connection, len(previousKeys))
+			weights := XavierInitializer{}.Weights(bp.Rand(), 1, len(previousKeys))[0]
+			for j, key := range previousKeys {
+				neuron.Connections[key] = Connection{Weight: weights[j]}
+			}
+		}
+		layer.Neurons[neuronID] = neuron
+	}
+}
+
+// sameKernelShape reports whether two kernels have the same number of rows and columns.
+func sameKernelShape(a, b [][]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+	}
+	return true
+}
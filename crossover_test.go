@@ -0,0 +1,62 @@
+package blueprint
+
+import "testing"
+
+func TestCrossoverLeavesParentsUntouched(t *testing.T) {
+	primary, other := newTestNetwork(11), newTestNetwork(12)
+	primary.Config.Metadata.ModelID, other.Config.Metadata.ModelID = "primary", "other"
+	primaryBefore, otherBefore := primary.Serialize(), other.Serialize()
+
+	child, err := primary.Crossover(other, "random", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if primary.Serialize() != primaryBefore || other.Serialize() != otherBefore {
+		t.Fatal("crossover modified a parent")
+	}
+	// The primary parent's source must not have been advanced by the crossover
+	fresh := newTestNetwork(11)
+	if got, want := primary.Rand().Int63(), fresh.Rand().Int63(); got != want {
+		t.Fatalf("primary parent drew %d after crossover, want %d", got, want)
+	}
+
+	metadata := child.Config.Metadata
+	if len(metadata.ParentModelIDs) != 2 || metadata.ParentModelIDs[0] != "primary" || metadata.ParentModelIDs[1] != "other" {
+		t.Fatalf("child parents %v, want [primary other]", metadata.ParentModelIDs)
+	}
+	if !metadata.Seeded || metadata.Seed != 5 {
+		t.Fatalf("child metadata %+v does not record seed 5", metadata)
+	}
+}
+
+func TestCrossoverSeedReproducesChild(t *testing.T) {
+	cross := func(seed int64) string {
+		primary, other := newTestNetwork(11), newTestNetwork(12)
+		child, err := primary.Crossover(other, "primary", seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return child.Serialize()
+	}
+	if cross(1) != cross(1) {
+		t.Fatal("the same seed bred different children")
+	}
+	if cross(1) == cross(2) {
+		t.Fatal("different seeds bred the same child")
+	}
+}
+
+func TestCrossoverRewiresUnconnectedNeurons(t *testing.T) {
+	primary, other := newTestNetwork(11), newTestNetwork(12)
+	neuron := primary.Config.Layers.Output.Neurons["neuron5"]
+	neuron.Connections = nil
+	primary.Config.Layers.Output.Neurons["neuron5"] = neuron
+
+	child, err := primary.Crossover(other, "primary", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(child.Config.Layers.Output.Neurons["neuron5"].Connections); got != 3 {
+		t.Fatalf("unconnected output has %d connections after crossover, want 3", got)
+	}
+}
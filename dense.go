@@ -1,9 +1,20 @@
 package blueprint
 
+import "math"
+
 // Example of processing a dense layer as a method of Blueprint
-func (bp *Blueprint) processDenseLayer(layer Layer, inputData interface{}) interface{} {
-	inputValues := inputData.(map[string]float64)
-	neurons := make(map[string]float64)
+func (bp *Blueprint) processDenseLayer(layer Layer, inputData interface{}) (interface{}, error) {
+	inputValues, ok := inputData.(map[string]float64)
+	if !ok {
+		return nil, shapeMismatch("map[string]float64", inputData)
+	}
+	_, neurons := bp.denseForward(layer, inputValues)
+	return neurons, nil
+}
+
+// denseForward computes the weighted sums and activations of every neuron in a dense layer.
+func (bp *Blueprint) denseForward(layer Layer, inputValues map[string]float64) (map[string]float64, map[string]float64) {
+	preActivations := make(map[string]float64, len(layer.Neurons))
 
 	for nodeID, node := range layer.Neurons {
 		sum := 0.0
@@ -11,7 +22,103 @@ func (bp *Blueprint) processDenseLayer(layer Layer, inputData interface{}) inter
 			sum += inputValues[inputID] * connection.Weight
 		}
 		sum += node.Bias
-		neurons[nodeID] = bp.Activate(node.ActivationType, sum)
+		preActivations[nodeID] = sum
+	}
+	return preActivations, bp.activateLayer(layer, preActivations)
+}
+
+// activateLayer applies each neuron's activation function. Neurons using softmax or log_softmax are
+// normalized together across the layer, so they form a valid probability distribution.
+func (bp *Blueprint) activateLayer(layer Layer, preActivations map[string]float64) map[string]float64 {
+	neurons := make(map[string]float64, len(layer.Neurons))
+	var softmaxIDs, logSoftmaxIDs []string
+
+	for nodeID, node := range layer.Neurons {
+		switch node.ActivationType {
+		case "softmax":
+			softmaxIDs = append(softmaxIDs, nodeID)
+		case "log_softmax":
+			logSoftmaxIDs = append(logSoftmaxIDs, nodeID)
+		default:
+			neurons[nodeID] = bp.Activate(node.ActivationType, preActivations[nodeID])
+		}
 	}
+
+	normalizeGroup := func(ids []string, normalize func([]float64)) {
+		if len(ids) == 0 {
+			return
+		}
+		values := make([]float64, len(ids))
+		for i, id := range ids {
+			values[i] = preActivations[id]
+		}
+		normalize(values)
+		for i, id := range ids {
+			neurons[id] = values[i]
+		}
+	}
+	normalizeGroup(softmaxIDs, softmaxInPlace)
+	normalizeGroup(logSoftmaxIDs, logSoftmaxInPlace)
+
 	return neurons
 }
+
+// activationGradients converts the gradient with respect to a dense layer's outputs into the gradient
+// with respect to its pre-activations, using the full Jacobian for softmax and log_softmax groups.
+func (bp *Blueprint) activationGradients(layer Layer, preActivations, outputs, gradOut map[string]float64) map[string]float64 {
+	deltas := make(map[string]float64, len(layer.Neurons))
+	softmaxDot, logSoftmaxSum := 0.0, 0.0
+
+	for nodeID, node := range layer.Neurons {
+		switch node.ActivationType {
+		case "softmax":
+			softmaxDot += gradOut[nodeID] * outputs[nodeID]
+		case "log_softmax":
+			logSoftmaxSum += gradOut[nodeID]
+		}
+	}
+
+	for nodeID, node := range layer.Neurons {
+		switch node.ActivationType {
+		case "softmax":
+			deltas[nodeID] = outputs[nodeID] * (gradOut[nodeID] - softmaxDot)
+		case "log_softmax":
+			deltas[nodeID] = gradOut[nodeID] - math.Exp(outputs[nodeID])*logSoftmaxSum
+		default:
+			deltas[nodeID] = gradOut[nodeID] * bp.ActivationDerivative(node.ActivationType, preActivations[nodeID])
+		}
+	}
+	return deltas
+}
+
+// softmaxInPlace replaces values with their softmax, subtracting the maximum first to avoid overflow.
+func softmaxInPlace(values []float64) {
+	maxValue := math.Inf(-1)
+	for _, v := range values {
+		maxValue = math.Max(maxValue, v)
+	}
+	sum := 0.0
+	for i, v := range values {
+		values[i] = math.Exp(v - maxValue)
+		sum += values[i]
+	}
+	for i := range values {
+		values[i] /= sum
+	}
+}
+
+// logSoftmaxInPlace replaces values with their log-softmax using the log-sum-exp trick.
+func logSoftmaxInPlace(values []float64) {
+	maxValue := math.Inf(-1)
+	for _, v := range values {
+		maxValue = math.Max(maxValue, v)
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += math.Exp(v - maxValue)
+	}
+	logSum := maxValue + math.Log(sum)
+	for i, v := range values {
+		values[i] = v - logSum
+	}
+}
@@ -0,0 +1,65 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSoftmaxOutputsAreNormalized(t *testing.T) {
+	bp := NewBlueprintWithSeed(&NetworkConfig{}, 4)
+	bp.CreateCustomNetworkConfig(3, 4, 3, []string{"softmax", "softmax", "softmax"}, "test", "test", NormalInitializer{StdDev: 1})
+	input := map[string]interface{}{"neuron0": 0.3, "neuron1": -0.7, "neuron2": 0.5}
+
+	sum := 0.0
+	for _, value := range bp.Feedforward(input) {
+		sum += value
+	}
+	if math.Abs(sum-1) > 1e-12 {
+		t.Fatalf("softmax outputs sum to %v, want 1", sum)
+	}
+
+	// A huge logit must saturate instead of overflowing to NaN
+	outputID := sortedKeys(bp.Config.Layers.Output.Neurons)[0]
+	neuron := bp.Config.Layers.Output.Neurons[outputID]
+	neuron.Bias = 1e5
+	bp.Config.Layers.Output.Neurons[outputID] = neuron
+	outputs := bp.Feedforward(input)
+	if math.Abs(outputs[outputID]-1) > 1e-12 {
+		t.Fatalf("saturated softmax output %v, want 1", outputs[outputID])
+	}
+	for outputID, value := range outputs {
+		if math.IsNaN(value) {
+			t.Fatalf("output %s is NaN", outputID)
+		}
+	}
+}
+
+func TestComputeGradientsSoftmaxJacobian(t *testing.T) {
+	input := map[string]interface{}{"neuron0": 0.3, "neuron1": -0.7, "neuron2": 0.5}
+
+	bp := NewBlueprintWithSeed(&NetworkConfig{}, 5)
+	bp.CreateCustomNetworkConfig(3, 4, 4, []string{"softmax", "softmax", "softmax", "softmax"}, "test", "test", NormalInitializer{StdDev: 1})
+	setActivations(&bp.Config.Layers.Hidden[0], "tanh")
+	checkGradients(t, bp, input, targetsFor(t, bp, input, 0, 1, 0, 0), CategoricalCrossEntropy{})
+
+	// Mean squared error exercises the off-diagonal Jacobian terms that cross-entropy cancels out
+	checkGradients(t, bp, input, targetsFor(t, bp, input, 0.2, 0.5, 0.1, 0.2), MeanSquaredError{})
+
+	// Separate softmax and log_softmax groups in one layer are normalized independently
+	setActivations(&bp.Config.Layers.Output, "log_softmax", "softmax")
+	checkGradients(t, bp, input, targetsFor(t, bp, input, -1, 1, -2), MeanSquaredError{})
+}
+
+func TestActivationDerivativeMatchesActivate(t *testing.T) {
+	bp := NewBlueprint(&NetworkConfig{})
+	activations := []string{"relu", "sigmoid", "tanh", "leaky_relu", "swish", "elu", "selu", "softplus", "linear"}
+	const h = 1e-6
+	for _, activation := range activations {
+		for _, x := range []float64{-1.3, -0.2, 0.4, 2.1} {
+			numeric := (bp.Activate(activation, x+h) - bp.Activate(activation, x-h)) / (2 * h)
+			if got := bp.ActivationDerivative(activation, x); math.Abs(got-numeric) > 1e-6 {
+				t.Errorf("%s'(%v) = %v, numeric %v", activation, x, got, numeric)
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// blueprint/errors.go
+package blueprint
+
+import "fmt"
+
+// MissingInputError reports an input the network needs that was not provided.
+type MissingInputError struct {
+	Key string
+}
+
+func (e *MissingInputError) Error() string {
+	return fmt.Sprintf("missing input %q", e.Key)
+}
+
+// InputTypeError reports an input value that does not have the type the input layer expects.
+type InputTypeError struct {
+	Key      string
+	Expected string
+	Actual   string
+}
+
+func (e *InputTypeError) Error() string {
+	return fmt.Sprintf("input %q is %s, expected %s", e.Key, e.Actual, e.Expected)
+}
+
+// ShapeMismatchError reports data whose type or shape does not match what a layer expects.
+type ShapeMismatchError struct {
+	Expected string
+	Actual   string
+}
+
+func (e *ShapeMismatchError) Error() string {
+	return fmt.Sprintf("shape mismatch: expected %s, got %s", e.Expected, e.Actual)
+}
+
+// UnknownLayerTypeError reports a layer type the network does not know how to process.
+type UnknownLayerTypeError struct {
+	LayerType string
+}
+
+func (e *UnknownLayerTypeError) Error() string {
+	return fmt.Sprintf("unknown layer type %q", e.LayerType)
+}
+
+// LayerError wraps an error with the layer it occurred in. LayerIndex counts hidden layers from 0 with
+// the output layer last, matching Gradients; the input layer is reported as -1.
+type LayerError struct {
+	LayerIndex int
+	LayerType  string
+	Err        error
+}
+
+func (e *LayerError) Error() string {
+	return fmt.Sprintf("layer %d (%s): %v", e.LayerIndex, e.LayerType, e.Err)
+}
+
+func (e *LayerError) Unwrap() error {
+	return e.Err
+}
+
+// shapeMismatch builds a ShapeMismatchError for data that is not of the expected type.
+func shapeMismatch(expected string, data interface{}) error {
+	return &ShapeMismatchError{Expected: expected, Actual: fmt.Sprintf("%T", data)}
+}
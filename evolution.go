@@ -0,0 +1,405 @@
+// blueprint/evolution.go
+package blueprint
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// FitnessFunc scores a Blueprint; higher is better.
+type FitnessFunc func(bp *Blueprint) float64
+
+// Individual is a member of an Evolver's population.
+type Individual struct {
+	Blueprint *Blueprint
+	Fitness   float64
+	Evaluated bool
+
+	// Set by speciation: the individual's species and its fitness after sharing within that species
+	SpeciesID       int
+	AdjustedFitness float64
+
+	// Set by ParetoFronts: the objective values, the index of the individual's front and its crowding distance.
+	// An Evolver computes the objective values once, when it evaluates the individual.
+	Objectives []float64
+	Rank       int
+	Crowding   float64
+}
+
+// Evolver maintains a population of Blueprints and improves it generation by generation through
+// selection, elitism, crossover and mutation with ApplyMutation.
+type Evolver struct {
+	Population []*Individual
+	Fitness    FitnessFunc
+
+	Selection       string  // "tournament", "truncation" or "roulette"
+	TournamentSize  int     // Individuals competing in each tournament
+	TruncationRatio float64 // Fraction of the best individuals eligible as parents in truncation selection
+	EliteCount      int     // Best individuals copied unchanged into the next generation
+
+	CrossoverRate     float64 // Probability that a child is bred from two parents before mutation
+	CrossoverStrategy string  // Strategy passed to Crossover for mismatched layer types
+
+	// Speciation, if set, groups the population into species every generation and parents are selected
+	// by their shared AdjustedFitness instead of their raw fitness.
+	Speciation *Speciation
+
+	// Objectives, if set, switch Step to NSGA-II multi-objective selection over these objectives.
+	// Selection, EliteCount and Speciation are then ignored.
+	Objectives []Objective
+
+	Mutation MutationConfig // Mutation applied to every child
+
+	// OnMutation, if set, is called with every child's mutation record. err is set when the drawn mutation
+	// could not be applied and the child was left unmutated.
+	OnMutation func(child *Blueprint, record MutationRecord, err error)
+
+	// MutationFailures counts the children left unmutated because their drawn mutation could not be applied.
+	MutationFailures int
+
+	Generation int
+
+	rng         *rand.Rand
+	idPrefix    string
+	basePrefix  string // idPrefix as set by NewEvolver, before any island suffix
+	nextChildID int
+}
+
+// NewEvolver creates a population of populationSize Blueprints from base. The first individual is an
+// unchanged copy; every other one receives a single mutation. The initial individuals are labelled as
+// generation 0 children of base, whose own metadata is left untouched. seed makes the whole run
+// reproducible.
+func NewEvolver(base *Blueprint, populationSize int, fitness FitnessFunc, seed int64) (*Evolver, error) {
+	if populationSize <= 0 {
+		return nil, fmt.Errorf("population size must be positive")
+	}
+	if fitness == nil {
+		return nil, fmt.Errorf("fitness function is required")
+	}
+
+	e := &Evolver{
+		Fitness:         fitness,
+		Selection:       "tournament",
+		TournamentSize:  3,
+		TruncationRatio: 0.5,
+		EliteCount:      1,
+
+		CrossoverRate:     0.25,
+		CrossoverStrategy: "primary",
+
+		Mutation: DefaultMutationConfig(),
+		rng:      rand.New(rand.NewSource(seed)),
+		idPrefix: base.Config.Metadata.ModelID,
+	}
+	if e.idPrefix == "" {
+		e.idPrefix = "model"
+	}
+	e.basePrefix = e.idPrefix
+
+	// Breed from a private copy so the lineage recorded in the parent does not reach the caller's base
+	template := base.Clone()
+	first := template.Clone()
+	first.SetSeed(e.rng.Int63())
+	e.recordLineage(first, 0, template)
+	e.Population = append(e.Population, &Individual{Blueprint: first})
+
+	for len(e.Population) < populationSize {
+		child, err := e.offspring(0, template)
+		if err != nil {
+			return nil, err
+		}
+		e.Population = append(e.Population, &Individual{Blueprint: child})
+	}
+	return e, nil
+}
+
+// Evaluate computes the fitness of every individual that has not been evaluated yet.
+func (e *Evolver) Evaluate() {
+	e.evaluate(e.Population)
+}
+
+// evaluate computes the fitness of the given individuals that have not been evaluated yet, followed by
+// their objective values when objectives are set.
+func (e *Evolver) evaluate(individuals []*Individual) {
+	for _, ind := range individuals {
+		if !ind.Evaluated {
+			ind.Fitness = e.Fitness(ind.Blueprint)
+			ind.Evaluated = true
+			ind.Blueprint.Config.Metadata.Evaluated = true
+		}
+		if len(e.Objectives) > 0 && len(ind.Objectives) != len(e.Objectives) {
+			ind.Objectives = objectiveValues(ind, e.Objectives)
+		}
+	}
+}
+
+// Step evaluates the population and replaces it with the next generation: the elites are kept unchanged
+// and the remaining places are filled with mutated children of selected parents, bred by crossover with
+// probability CrossoverRate.
+func (e *Evolver) Step() error {
+	if err := e.Mutation.Validate(); err != nil {
+		return fmt.Errorf("invalid mutation config: %w", err)
+	}
+	if len(e.Objectives) > 0 {
+		return e.stepNSGA2()
+	}
+	e.Evaluate()
+	e.sortByFitness()
+	if e.Speciation != nil {
+		e.Speciation.Speciate(e.Population)
+	}
+
+	next := make([]*Individual, 0, len(e.Population))
+	for i := 0; i < e.EliteCount && i < len(e.Population); i++ {
+		next = append(next, e.Population[i])
+	}
+
+	for len(next) < len(e.Population) {
+		child, err := e.breed()
+		if err != nil {
+			return err
+		}
+		next = append(next, &Individual{Blueprint: child})
+	}
+
+	e.Population = next
+	e.Generation++
+	return nil
+}
+
+// Run advances the given number of generations and returns the best individual of the final population.
+// With Objectives set, the best individual is still the one with the highest fitness; use RunPareto to
+// get the trade-offs between the objectives.
+func (e *Evolver) Run(generations int) (*Individual, error) {
+	if err := e.advance(generations); err != nil {
+		return nil, err
+	}
+	return e.Best(), nil
+}
+
+// RunPareto advances the given number of generations and returns the Pareto front of the final
+// population under the Evolver's Objectives, or nil when no objectives are set.
+func (e *Evolver) RunPareto(generations int) ([]*Individual, error) {
+	if err := e.advance(generations); err != nil {
+		return nil, err
+	}
+	return e.ParetoFront(), nil
+}
+
+// advance runs the given number of generations.
+func (e *Evolver) advance(generations int) error {
+	for i := 0; i < generations; i++ {
+		if err := e.Step(); err != nil {
+			return fmt.Errorf("generation %d: %w", e.Generation, err)
+		}
+	}
+	return nil
+}
+
+// Best evaluates the population and returns its fittest individual.
+func (e *Evolver) Best() *Individual {
+	e.Evaluate()
+	e.sortByFitness()
+	return e.Population[0]
+}
+
+// sortByFitness orders the population from fittest to weakest.
+func (e *Evolver) sortByFitness() {
+	sort.SliceStable(e.Population, func(i, j int) bool {
+		return e.Population[i].Fitness > e.Population[j].Fitness
+	})
+}
+
+// rank evaluates the population and orders it from most to least preferred: by crowded comparison when
+// objectives are set and by fitness otherwise.
+func (e *Evolver) rank() {
+	e.Evaluate()
+	if len(e.Objectives) == 0 {
+		e.sortByFitness()
+		return
+	}
+	sortFronts(e.Population, e.Objectives)
+	sort.SliceStable(e.Population, func(i, j int) bool {
+		return crowdedLess(e.Population[i], e.Population[j])
+	})
+}
+
+// breed selects one or two parents and returns their mutated child.
+func (e *Evolver) breed() (*Blueprint, error) {
+	first, err := e.selectParent()
+	if err != nil {
+		return nil, err
+	}
+	if len(e.Population) < 2 || e.rng.Float64() >= e.CrossoverRate {
+		return e.offspring(e.Generation+1, first.Blueprint)
+	}
+
+	second, err := e.selectParent()
+	if err != nil {
+		return nil, err
+	}
+	// The fitter parent is the primary one, whose disjoint genes the child inherits
+	if e.fitter(second, first) {
+		first, second = second, first
+	}
+	return e.offspring(e.Generation+1, first.Blueprint, second.Blueprint)
+}
+
+// offspring returns a mutated child of one parent, or of the crossover of two parents, with a new model
+// ID for the given generation, recording the lineage in the metadata of the child and its parents.
+func (e *Evolver) offspring(generation int, parents ...*Blueprint) (*Blueprint, error) {
+	var child *Blueprint
+	if len(parents) == 2 {
+		var err error
+		if child, err = parents[0].Crossover(parents[1], e.CrossoverStrategy, e.rng.Int63()); err != nil {
+			return nil, err
+		}
+	} else {
+		child = parents[0].Clone()
+	}
+	child.SetSeed(e.rng.Int63())
+	record, err := child.ApplyMutation(e.Mutation)
+	if err != nil {
+		e.MutationFailures++
+	}
+	if e.OnMutation != nil {
+		e.OnMutation(child, record, err)
+	}
+	e.recordLineage(child, generation, parents...)
+	return child, nil
+}
+
+// recordLineage gives child a fresh model ID labelled with its generation, resets its evaluation state
+// and links it to its parents.
+func (e *Evolver) recordLineage(child *Blueprint, generation int, parents ...*Blueprint) {
+	childID := e.nextModelID(generation)
+
+	metadata := &child.Config.Metadata
+	metadata.ModelID = childID
+	metadata.Evaluated = false
+	metadata.ChildModelIDs = nil
+	metadata.ParentModelIDs = nil
+	for _, parent := range parents {
+		metadata.ParentModelIDs = append(metadata.ParentModelIDs, parent.Config.Metadata.ModelID)
+		parent.Config.Metadata.ChildModelIDs = append(parent.Config.Metadata.ChildModelIDs, childID)
+	}
+}
+
+// nextModelID returns a new model ID labelled with the given generation.
+func (e *Evolver) nextModelID(generation int) string {
+	id := fmt.Sprintf("%s_g%d_%d", e.idPrefix, generation, e.nextChildID)
+	e.nextChildID++
+	return id
+}
+
+// setIDPrefix changes the prefix of new model IDs. A population that has not been stepped yet has no
+// descendants outside it, so its individuals are renamed with the new prefix, along with the parent and
+// child IDs that refer to them.
+func (e *Evolver) setIDPrefix(prefix string) {
+	e.idPrefix = prefix
+	if e.Generation > 0 {
+		return
+	}
+	e.nextChildID = 0
+	renamed := make(map[string]string, len(e.Population))
+	for _, ind := range e.Population {
+		metadata := &ind.Blueprint.Config.Metadata
+		id := e.nextModelID(0)
+		renamed[metadata.ModelID] = id
+		metadata.ModelID = id
+	}
+	for _, ind := range e.Population {
+		metadata := &ind.Blueprint.Config.Metadata
+		for _, ids := range [][]string{metadata.ParentModelIDs, metadata.ChildModelIDs} {
+			for i, id := range ids {
+				if newID, ok := renamed[id]; ok {
+					ids[i] = newID
+				}
+			}
+		}
+	}
+}
+
+// fitter reports whether a should be preferred to b as a parent.
+func (e *Evolver) fitter(a, b *Individual) bool {
+	if len(e.Objectives) > 0 {
+		return crowdedLess(a, b)
+	}
+	return a.Fitness > b.Fitness
+}
+
+// selectParent picks a parent from the evaluated population using the configured selection method, or
+// by crowded tournament when objectives are set.
+func (e *Evolver) selectParent() (*Individual, error) {
+	if len(e.Objectives) > 0 {
+		return e.crowdedTournamentSelect(), nil
+	}
+	switch e.Selection {
+	case "tournament":
+		return e.tournamentSelect(), nil
+	case "truncation":
+		return e.truncationSelect(), nil
+	case "roulette":
+		return e.rouletteSelect(), nil
+	default:
+		return nil, fmt.Errorf("unknown selection method: %s", e.Selection)
+	}
+}
+
+// selectionFitness returns the fitness parents are selected by: the shared fitness when speciation is
+// enabled and the raw fitness otherwise.
+func (e *Evolver) selectionFitness(ind *Individual) float64 {
+	if e.Speciation != nil {
+		return ind.AdjustedFitness
+	}
+	return ind.Fitness
+}
+
+// tournamentSelect returns the fittest of TournamentSize randomly drawn individuals.
+func (e *Evolver) tournamentSelect() *Individual {
+	var best *Individual
+	for i := 0; i < max(e.TournamentSize, 1); i++ {
+		candidate := e.Population[e.rng.Intn(len(e.Population))]
+		if best == nil || e.selectionFitness(candidate) > e.selectionFitness(best) {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// truncationSelect returns a random individual from the best TruncationRatio of the population.
+func (e *Evolver) truncationSelect() *Individual {
+	ranked := append([]*Individual(nil), e.Population...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return e.selectionFitness(ranked[i]) > e.selectionFitness(ranked[j])
+	})
+	cutoff := int(math.Ceil(float64(len(ranked)) * e.TruncationRatio))
+	cutoff = min(max(cutoff, 1), len(ranked))
+	return ranked[e.rng.Intn(cutoff)]
+}
+
+// rouletteSelect returns an individual with probability proportional to its fitness, shifted so the
+// weakest individual still has a small chance.
+func (e *Evolver) rouletteSelect() *Individual {
+	minFitness := math.Inf(1)
+	for _, ind := range e.Population {
+		minFitness = math.Min(minFitness, e.selectionFitness(ind))
+	}
+
+	const floor = 1e-9
+	total := 0.0
+	for _, ind := range e.Population {
+		total += e.selectionFitness(ind) - minFitness + floor
+	}
+
+	pick := e.rng.Float64() * total
+	for _, ind := range e.Population {
+		pick -= e.selectionFitness(ind) - minFitness + floor
+		if pick <= 0 {
+			return ind
+		}
+	}
+	return e.Population[len(e.Population)-1]
+}
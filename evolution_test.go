@@ -0,0 +1,65 @@
+package blueprint
+
+import (
+	"strings"
+	"testing"
+)
+
+// parameterCount scores a Blueprint by its number of dense parameters, which is deterministic.
+func parameterCount(bp *Blueprint) float64 {
+	return float64(len(parameters(bp)))
+}
+
+func TestNewEvolverLeavesBaseUntouched(t *testing.T) {
+	base := newTestNetwork(3)
+	before := base.Serialize()
+	e, err := NewEvolver(base, 4, parameterCount, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base.Serialize() != before {
+		t.Fatalf("NewEvolver changed the base: %+v", base.Config.Metadata)
+	}
+
+	for _, ind := range e.Population {
+		metadata := ind.Blueprint.Config.Metadata
+		if !strings.HasPrefix(metadata.ModelID, "test_g0_") {
+			t.Errorf("initial individual %s is not labelled generation 0", metadata.ModelID)
+		}
+		if len(metadata.ParentModelIDs) != 1 || metadata.ParentModelIDs[0] != "test" {
+			t.Errorf("initial individual %s has parents %v, want [test]", metadata.ModelID, metadata.ParentModelIDs)
+		}
+	}
+
+	if err := e.Step(); err != nil {
+		t.Fatal(err)
+	}
+	for _, ind := range e.Population[e.EliteCount:] {
+		if id := ind.Blueprint.Config.Metadata.ModelID; !strings.HasPrefix(id, "test_g1_") {
+			t.Errorf("child %s of the first step is not labelled generation 1", id)
+		}
+	}
+}
+
+func TestEvolverSeedReproducesRun(t *testing.T) {
+	run := func(seed int64) []string {
+		e, err := NewEvolver(newTestNetwork(3), 6, parameterCount, seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := e.Run(3); err != nil {
+			t.Fatal(err)
+		}
+		var configs []string
+		for _, ind := range e.Population {
+			configs = append(configs, ind.Blueprint.Serialize())
+		}
+		return configs
+	}
+	first, second := run(5), run(5)
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("individual %d differs between runs with the same seed", i)
+		}
+	}
+}
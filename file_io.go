@@ -37,3 +37,34 @@ func (bp *Blueprint) LoadModel(filePath string) error {
 	bp.Config = &modelConfig
 	return nil
 }
+
+// SaveOptimizer saves an optimizer's state to a specified file so training can be resumed later.
+func (bp *Blueprint) SaveOptimizer(filePath string, optimizer Optimizer) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create optimizer file: %w", err)
+	}
+	defer file.Close()
+
+	if err := json.NewEncoder(file).Encode(optimizer.State()); err != nil {
+		return fmt.Errorf("failed to encode optimizer: %w", err)
+	}
+
+	return nil
+}
+
+// LoadOptimizer restores an optimizer from a file written by SaveOptimizer.
+func (bp *Blueprint) LoadOptimizer(filePath string) (Optimizer, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open optimizer file: %w", err)
+	}
+	defer file.Close()
+
+	var state OptimizerState
+	if err := json.NewDecoder(file).Decode(&state); err != nil {
+		return nil, fmt.Errorf("failed to decode optimizer: %w", err)
+	}
+
+	return NewOptimizerFromState(state)
+}
@@ -0,0 +1,41 @@
+// blueprint/flops.go
+package blueprint
+
+// EstimateFLOPs estimates the floating-point operations of one forward pass. A multiply-add counts as two
+// operations and a bias addition as one; activation functions are not counted. Dense layers cost
+// 2*inputs+1 per neuron, conv layers 2*kernel+1 per output value and LSTM layers 8*width+8 per cell and
+// time step. Conv and LSTM input layers need Layer.Shape so the image size and sequence length are known.
+func (bp *Blueprint) EstimateFLOPs() (int64, error) {
+	shapes, err := bp.InferShapes()
+	if err != nil {
+		return 0, err
+	}
+
+	// InferShapes has already rejected unknown layer types and malformed filters
+	var total int64
+	for i, layer := range bp.trainableLayers() {
+		in := shapes[i]
+		switch layer.LayerType {
+		case "dense":
+			for _, neuron := range layer.Neurons {
+				total += int64(2*len(neuron.Connections) + 1)
+			}
+
+		case "conv":
+			for _, filter := range layer.Filters {
+				kernel := len(filter.Weights) * len(filter.Weights[0])
+				outHeight := (in.Height+2*layer.Padding-len(filter.Weights))/layer.Stride + 1
+				outWidth := (in.Width+2*layer.Padding-len(filter.Weights[0]))/layer.Stride + 1
+				total += int64(in.Channels*outHeight*outWidth) * int64(2*kernel+1)
+			}
+
+		case "lstm":
+			steps := 1
+			if in.Kind == "sequence" && in.Steps > 0 {
+				steps = in.Steps
+			}
+			total += int64(steps*len(layer.LSTMCells)) * int64(8*in.featureWidth()+8)
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,9 @@
+package blueprint
+
+// newTestNetwork returns a seeded 2-3-1 dense network with inputs neuron0 and neuron1, hidden neurons
+// neuron2 to neuron4 and the sigmoid output neuron5.
+func newTestNetwork(seed int64) *Blueprint {
+	bp := NewBlueprintWithSeed(&NetworkConfig{}, seed)
+	bp.CreateCustomNetworkConfig(2, 3, 1, nil, "test", "test")
+	return bp
+}
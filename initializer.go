@@ -0,0 +1,243 @@
+// blueprint/initializer.go
+package blueprint
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Initializer generates initial parameters for a layer. Weights returns rows weight vectors of length
+// cols, one per neuron, filter or LSTM gate, treating cols as the fan-in and rows as the fan-out.
+type Initializer interface {
+	Weights(rng *rand.Rand, rows, cols int) [][]float64
+	Bias(rng *rand.Rand) float64
+}
+
+// NewInitializer returns the initializer registered under the given name.
+func NewInitializer(name string) (Initializer, error) {
+	switch name {
+	case "uniform":
+		return UniformInitializer{Low: 0, High: 1}, nil
+	case "normal":
+		return NormalInitializer{StdDev: 1}, nil
+	case "xavier", "glorot":
+		return XavierInitializer{}, nil
+	case "xavier_uniform", "glorot_uniform":
+		return XavierInitializer{Uniform: true}, nil
+	case "he", "kaiming":
+		return HeInitializer{}, nil
+	case "he_uniform", "kaiming_uniform":
+		return HeInitializer{Uniform: true}, nil
+	case "lecun":
+		return LeCunInitializer{}, nil
+	case "lecun_uniform":
+		return LeCunInitializer{Uniform: true}, nil
+	case "orthogonal":
+		return OrthogonalInitializer{Gain: 1}, nil
+	case "zeros":
+		return ZeroInitializer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown initializer: %s", name)
+	}
+}
+
+// pickInitializer returns the first optional initializer, or defaultInit when none was given.
+func pickInitializer(defaultInit Initializer, initializer []Initializer) Initializer {
+	if len(initializer) > 0 && initializer[0] != nil {
+		return initializer[0]
+	}
+	return defaultInit
+}
+
+// UniformInitializer draws weights and biases uniformly from [Low, High).
+type UniformInitializer struct {
+	Low, High float64
+}
+
+// Weights returns uniformly distributed weights.
+func (u UniformInitializer) Weights(rng *rand.Rand, rows, cols int) [][]float64 {
+	return fillWeights(rows, cols, func() float64 { return u.Bias(rng) })
+}
+
+// Bias returns a uniformly distributed bias.
+func (u UniformInitializer) Bias(rng *rand.Rand) float64 {
+	return u.Low + rng.Float64()*(u.High-u.Low)
+}
+
+// NormalInitializer draws weights and biases from a normal distribution with mean 0.
+type NormalInitializer struct {
+	StdDev float64
+}
+
+// Weights returns normally distributed weights.
+func (n NormalInitializer) Weights(rng *rand.Rand, rows, cols int) [][]float64 {
+	return fillWeights(rows, cols, func() float64 { return n.Bias(rng) })
+}
+
+// Bias returns a normally distributed bias.
+func (n NormalInitializer) Bias(rng *rand.Rand) float64 {
+	return rng.NormFloat64() * n.StdDev
+}
+
+// XavierInitializer implements Xavier/Glorot initialization, scaling by fan-in and fan-out.
+// It suits sigmoid and tanh activations. Biases start at zero.
+type XavierInitializer struct {
+	Uniform bool
+}
+
+// Weights returns Xavier-scaled weights.
+func (x XavierInitializer) Weights(rng *rand.Rand, rows, cols int) [][]float64 {
+	return scaledWeights(rng, rows, cols, x.variance(cols, rows), x.Uniform)
+}
+
+// variance returns the weight variance for the given fan-in and fan-out.
+func (XavierInitializer) variance(fanIn, fanOut int) float64 {
+	return 2 / float64(max(fanIn+fanOut, 1))
+}
+
+// Bias returns zero.
+func (XavierInitializer) Bias(*rand.Rand) float64 { return 0 }
+
+// HeInitializer implements He/Kaiming initialization, scaling by fan-in. It suits ReLU-like activations.
+// Biases start at zero.
+type HeInitializer struct {
+	Uniform bool
+}
+
+// Weights returns He-scaled weights.
+func (h HeInitializer) Weights(rng *rand.Rand, rows, cols int) [][]float64 {
+	return scaledWeights(rng, rows, cols, h.variance(cols, rows), h.Uniform)
+}
+
+// variance returns the weight variance for the given fan-in.
+func (HeInitializer) variance(fanIn, _ int) float64 { return 2 / float64(max(fanIn, 1)) }
+
+// Bias returns zero.
+func (HeInitializer) Bias(*rand.Rand) float64 { return 0 }
+
+// LeCunInitializer implements LeCun initialization, scaling by fan-in. It suits SELU activations.
+// Biases start at zero.
+type LeCunInitializer struct {
+	Uniform bool
+}
+
+// Weights returns LeCun-scaled weights.
+func (l LeCunInitializer) Weights(rng *rand.Rand, rows, cols int) [][]float64 {
+	return scaledWeights(rng, rows, cols, l.variance(cols, rows), l.Uniform)
+}
+
+// variance returns the weight variance for the given fan-in.
+func (LeCunInitializer) variance(fanIn, _ int) float64 { return 1 / float64(max(fanIn, 1)) }
+
+// Bias returns zero.
+func (LeCunInitializer) Bias(*rand.Rand) float64 { return 0 }
+
+// OrthogonalInitializer produces weight matrices with orthonormal rows (or columns, when there are more
+// rows than columns), multiplied by Gain. Biases start at zero.
+type OrthogonalInitializer struct {
+	Gain float64
+}
+
+// Weights returns an orthogonal weight matrix.
+func (o OrthogonalInitializer) Weights(rng *rand.Rand, rows, cols int) [][]float64 {
+	gain := o.Gain
+	if gain == 0 {
+		gain = 1
+	}
+
+	// Orthonormalize the shorter dimension of a random normal matrix with Gram-Schmidt
+	transpose := rows > cols
+	n, m := rows, cols
+	if transpose {
+		n, m = cols, rows
+	}
+	vectors := fillWeights(n, m, rng.NormFloat64)
+	for i := range vectors {
+		for j := 0; j < i; j++ {
+			dot := 0.0
+			for k := range vectors[i] {
+				dot += vectors[i][k] * vectors[j][k]
+			}
+			for k := range vectors[i] {
+				vectors[i][k] -= dot * vectors[j][k]
+			}
+		}
+		norm := 0.0
+		for _, v := range vectors[i] {
+			norm += v * v
+		}
+		norm = math.Sqrt(norm)
+		for k := range vectors[i] {
+			if norm > 0 {
+				vectors[i][k] /= norm
+			}
+		}
+	}
+
+	return fillWeightsIndexed(rows, cols, func(r, c int) float64 {
+		if transpose {
+			return gain * vectors[c][r]
+		}
+		return gain * vectors[r][c]
+	})
+}
+
+// Bias returns zero.
+func (OrthogonalInitializer) Bias(*rand.Rand) float64 { return 0 }
+
+// ZeroInitializer sets every weight and bias to zero.
+type ZeroInitializer struct{}
+
+// Weights returns zero weights.
+func (ZeroInitializer) Weights(_ *rand.Rand, rows, cols int) [][]float64 {
+	return fillWeights(rows, cols, func() float64 { return 0 })
+}
+
+// Bias returns zero.
+func (ZeroInitializer) Bias(*rand.Rand) float64 { return 0 }
+
+// drawWeight returns a single weight drawn as initializer would for a layer with the given fan-in and
+// fan-out. Scaled and element-wise initializers draw just one value; orthogonal and custom initializers
+// need the whole matrix, so one is generated and its first entry returned.
+func drawWeight(rng *rand.Rand, initializer Initializer, fanIn, fanOut int) float64 {
+	switch init := initializer.(type) {
+	case XavierInitializer:
+		return scaledWeights(rng, 1, 1, init.variance(fanIn, fanOut), init.Uniform)[0][0]
+	case HeInitializer:
+		return scaledWeights(rng, 1, 1, init.variance(fanIn, fanOut), init.Uniform)[0][0]
+	case LeCunInitializer:
+		return scaledWeights(rng, 1, 1, init.variance(fanIn, fanOut), init.Uniform)[0][0]
+	case UniformInitializer, NormalInitializer, ZeroInitializer:
+		return initializer.Weights(rng, 1, 1)[0][0]
+	default:
+		return initializer.Weights(rng, max(fanOut, 1), max(fanIn, 1))[0][0]
+	}
+}
+
+// scaledWeights draws weights with the given variance from a uniform or normal distribution.
+func scaledWeights(rng *rand.Rand, rows, cols int, variance float64, uniform bool) [][]float64 {
+	if uniform {
+		limit := math.Sqrt(3 * variance)
+		return fillWeights(rows, cols, func() float64 { return (rng.Float64()*2 - 1) * limit })
+	}
+	stdDev := math.Sqrt(variance)
+	return fillWeights(rows, cols, func() float64 { return rng.NormFloat64() * stdDev })
+}
+
+// fillWeights builds a rows x cols matrix from successive values of next.
+func fillWeights(rows, cols int, next func() float64) [][]float64 {
+	return fillWeightsIndexed(rows, cols, func(int, int) float64 { return next() })
+}
+
+// fillWeightsIndexed builds a rows x cols matrix from the value of each position.
+func fillWeightsIndexed(rows, cols int, value func(r, c int) float64) [][]float64 {
+	weights := make([][]float64, rows)
+	for r := range weights {
+		weights[r] = make([]float64, cols)
+		for c := range weights[r] {
+			weights[r][c] = value(r, c)
+		}
+	}
+	return weights
+}
@@ -0,0 +1,135 @@
+package blueprint
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestNewInitializer(t *testing.T) {
+	for name, want := range map[string]Initializer{
+		"uniform":         UniformInitializer{Low: 0, High: 1},
+		"normal":          NormalInitializer{StdDev: 1},
+		"xavier":          XavierInitializer{},
+		"glorot_uniform":  XavierInitializer{Uniform: true},
+		"he":              HeInitializer{},
+		"kaiming_uniform": HeInitializer{Uniform: true},
+		"lecun":           LeCunInitializer{},
+		"lecun_uniform":   LeCunInitializer{Uniform: true},
+		"orthogonal":      OrthogonalInitializer{Gain: 1},
+		"zeros":           ZeroInitializer{},
+	} {
+		got, err := NewInitializer(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got != want {
+			t.Errorf("%s: got %#v, want %#v", name, got, want)
+		}
+	}
+	if _, err := NewInitializer("bogus"); err == nil {
+		t.Error("unknown initializer name accepted")
+	}
+}
+
+func TestInitializerVariance(t *testing.T) {
+	const fanOut, fanIn = 200, 300
+	for _, tc := range []struct {
+		name     string
+		init     Initializer
+		variance float64
+	}{
+		{"xavier", XavierInitializer{}, 2.0 / (fanIn + fanOut)},
+		{"xavier_uniform", XavierInitializer{Uniform: true}, 2.0 / (fanIn + fanOut)},
+		{"he", HeInitializer{}, 2.0 / fanIn},
+		{"he_uniform", HeInitializer{Uniform: true}, 2.0 / fanIn},
+		{"lecun", LeCunInitializer{}, 1.0 / fanIn},
+		{"lecun_uniform", LeCunInitializer{Uniform: true}, 1.0 / fanIn},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			weights := tc.init.Weights(rand.New(rand.NewSource(1)), fanOut, fanIn)
+			if len(weights) != fanOut || len(weights[0]) != fanIn {
+				t.Fatalf("got a %dx%d matrix, want %dx%d", len(weights), len(weights[0]), fanOut, fanIn)
+			}
+			sum, sumSquares := 0.0, 0.0
+			for _, row := range weights {
+				for _, w := range row {
+					sum += w
+					sumSquares += w * w
+				}
+			}
+			n := float64(fanIn * fanOut)
+			mean := sum / n
+			variance := sumSquares/n - mean*mean
+			if math.Abs(mean) > 0.05*math.Sqrt(tc.variance) {
+				t.Errorf("mean %v is not close to 0", mean)
+			}
+			if math.Abs(variance-tc.variance) > 0.03*tc.variance {
+				t.Errorf("variance %v, want %v", variance, tc.variance)
+			}
+			if bias := tc.init.Bias(rand.New(rand.NewSource(1))); bias != 0 {
+				t.Errorf("bias %v, want 0", bias)
+			}
+		})
+	}
+}
+
+func TestOrthogonalInitializer(t *testing.T) {
+	for _, tc := range []struct {
+		rows, cols int
+		gain       float64
+	}{
+		{4, 6, 1},
+		{6, 6, 1},
+		{6, 3, 2},
+	} {
+		weights := OrthogonalInitializer{Gain: tc.gain}.Weights(rand.New(rand.NewSource(2)), tc.rows, tc.cols)
+
+		// Rows are orthonormal when there are no more rows than columns, and columns otherwise
+		n, m := tc.rows, tc.cols
+		at := func(i, k int) float64 { return weights[i][k] }
+		if tc.rows > tc.cols {
+			n, m = tc.cols, tc.rows
+			at = func(i, k int) float64 { return weights[k][i] }
+		}
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				dot := 0.0
+				for k := 0; k < m; k++ {
+					dot += at(i, k) * at(j, k)
+				}
+				want := 0.0
+				if i == j {
+					want = tc.gain * tc.gain
+				}
+				if math.Abs(dot-want) > 1e-9 {
+					t.Errorf("%dx%d: product[%d][%d] = %v, want %v", tc.rows, tc.cols, i, j, dot, want)
+				}
+			}
+		}
+	}
+}
+
+func TestDrawWeightUsesLayerShape(t *testing.T) {
+	const fanIn, fanOut, draws = 3, 47, 40000
+	rng := rand.New(rand.NewSource(3))
+	for _, tc := range []struct {
+		init     Initializer
+		variance float64
+	}{
+		{XavierInitializer{}, 2.0 / (fanIn + fanOut)},
+		{HeInitializer{Uniform: true}, 2.0 / fanIn},
+	} {
+		sumSquares := 0.0
+		for i := 0; i < draws; i++ {
+			w := drawWeight(rng, tc.init, fanIn, fanOut)
+			sumSquares += w * w
+		}
+		if variance := sumSquares / draws; math.Abs(variance-tc.variance) > 0.05*tc.variance {
+			t.Errorf("%#v: variance %v, want %v", tc.init, variance, tc.variance)
+		}
+	}
+	if w := drawWeight(rng, OrthogonalInitializer{Gain: 1}, fanIn, fanOut); math.Abs(w) > 1 {
+		t.Errorf("orthogonal weight %v is outside [-1, 1]", w)
+	}
+}
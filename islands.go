@@ -0,0 +1,159 @@
+// blueprint/islands.go
+package blueprint
+
+import (
+	"fmt"
+	"sync"
+)
+
+// IslandModel evolves several independent populations, each with its own Evolver settings, and
+// periodically sends copies of each island's best individuals to its neighbours.
+type IslandModel struct {
+	Islands []*Evolver
+
+	Topology          string // "ring" sends migrants to the next island, "fully_connected" to every other one
+	MigrationInterval int    // Generations between migrations; 0 disables migration
+	MigrationSize     int    // Best individuals each island sends per migration
+}
+
+// NewIslandModel creates an island model over the given Evolvers, migrating one individual around a ring
+// every 5 generations. Each island's model IDs get the suffix "_i<index>" after the prefix taken from its
+// base model, replacing any suffix from an earlier island model, so that IDs stay unique across islands;
+// initial populations that have not been stepped yet are renamed accordingly.
+func NewIslandModel(islands ...*Evolver) (*IslandModel, error) {
+	if len(islands) == 0 {
+		return nil, fmt.Errorf("at least one island is required")
+	}
+	seen := make(map[*Evolver]int, len(islands))
+	for i, island := range islands {
+		if island == nil {
+			return nil, fmt.Errorf("island %d is nil", i)
+		}
+		if j, ok := seen[island]; ok {
+			return nil, fmt.Errorf("islands %d and %d are the same Evolver", j, i)
+		}
+		seen[island] = i
+	}
+
+	for i, island := range islands {
+		island.setIDPrefix(fmt.Sprintf("%s_i%d", island.basePrefix, i))
+	}
+	return &IslandModel{
+		Islands:           islands,
+		Topology:          "ring",
+		MigrationInterval: 5,
+		MigrationSize:     1,
+	}, nil
+}
+
+// Run advances every island by the given number of generations, stepping the islands concurrently and
+// migrating whenever the first island's Generation reaches a multiple of MigrationInterval, so repeated
+// short runs migrate too. It returns the best individual across all islands. The islands' fitness
+// functions must be safe for concurrent use.
+func (m *IslandModel) Run(generations int) (*Individual, error) {
+	if _, err := m.destinations(0); err != nil {
+		return nil, err
+	}
+
+	for g := 0; g < generations; g++ {
+		errs := make([]error, len(m.Islands))
+		var wg sync.WaitGroup
+		for i, island := range m.Islands {
+			wg.Add(1)
+			go func(i int, island *Evolver) {
+				defer wg.Done()
+				errs[i] = island.Step()
+			}(i, island)
+		}
+		wg.Wait()
+		for i, err := range errs {
+			if err != nil {
+				return nil, fmt.Errorf("island %d: %w", i, err)
+			}
+		}
+
+		if m.MigrationInterval > 0 && m.Islands[0].Generation%m.MigrationInterval == 0 {
+			if err := m.Migrate(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return m.Best(), nil
+}
+
+// Migrate sends clones of the MigrationSize best individuals of every island to its destinations under
+// the topology. Migrants replace the weakest individuals of the receiving island, which always keeps its
+// own best individual, and are evaluated again by the receiving island's fitness function. Islands with
+// Objectives rank their individuals by crowded comparison instead of fitness. Every migrant gets a new
+// model ID from its receiving island and records the emigrant as its parent.
+func (m *IslandModel) Migrate() error {
+	if m.MigrationSize <= 0 || len(m.Islands) < 2 {
+		return nil
+	}
+
+	// Choose all emigrants before any island receives immigrants
+	emigrants := make([][]*Individual, len(m.Islands))
+	for i, island := range m.Islands {
+		island.rank()
+		emigrants[i] = island.Population[:min(m.MigrationSize, len(island.Population))]
+	}
+
+	incoming := make([][]*Individual, len(m.Islands))
+	for i := range m.Islands {
+		targets, err := m.destinations(i)
+		if err != nil {
+			return err
+		}
+		for _, target := range targets {
+			for _, emigrant := range emigrants[i] {
+				receiver := m.Islands[target]
+				migrant := emigrant.Blueprint.Clone()
+				migrant.SetSeed(receiver.rng.Int63())
+				receiver.recordLineage(migrant, receiver.Generation, emigrant.Blueprint)
+				incoming[target] = append(incoming[target], &Individual{Blueprint: migrant})
+			}
+		}
+	}
+
+	for target, island := range m.Islands {
+		// The population is ranked from best to worst, so the weakest places are at the end
+		count := min(len(incoming[target]), len(island.Population)-1)
+		for k := 0; k < count; k++ {
+			island.Population[len(island.Population)-1-k] = incoming[target][k]
+		}
+	}
+	return nil
+}
+
+// Best returns the fittest individual across all islands.
+func (m *IslandModel) Best() *Individual {
+	var best *Individual
+	for _, island := range m.Islands {
+		if candidate := island.Best(); best == nil || candidate.Fitness > best.Fitness {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// destinations returns the indices of the islands that island i sends migrants to.
+func (m *IslandModel) destinations(i int) ([]int, error) {
+	n := len(m.Islands)
+	switch m.Topology {
+	case "ring":
+		if n < 2 {
+			return nil, nil
+		}
+		return []int{(i + 1) % n}, nil
+	case "fully_connected":
+		var targets []int
+		for j := 0; j < n; j++ {
+			if j != i {
+				targets = append(targets, j)
+			}
+		}
+		return targets, nil
+	default:
+		return nil, fmt.Errorf("unknown migration topology: %s", m.Topology)
+	}
+}
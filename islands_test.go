@@ -0,0 +1,195 @@
+package blueprint
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// newTestIslands returns n islands bred from the same base with their populations scored so that the
+// individual at index 0 of island i is the fittest, with fitness 100+i.
+func newTestIslands(t *testing.T, n int) *IslandModel {
+	var islands []*Evolver
+	for i := 0; i < n; i++ {
+		e, err := NewEvolver(newTestNetwork(3), 4, parameterCount, int64(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		islands = append(islands, e)
+	}
+	m, err := NewIslandModel(islands...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, island := range m.Islands {
+		for j, ind := range island.Population {
+			ind.Fitness, ind.Evaluated = float64(100+i-10*j), true
+		}
+	}
+	return m
+}
+
+// islandOf returns the index of the island whose prefix the model ID carries, or "" for IDs from
+// outside the island model.
+func islandOf(modelID string) string {
+	_, rest, ok := strings.Cut(modelID, "_i")
+	if !ok {
+		return ""
+	}
+	index, _, _ := strings.Cut(rest, "_")
+	return index
+}
+
+func TestNewIslandModelMakesIDsUnique(t *testing.T) {
+	m := newTestIslands(t, 3)
+	seen := make(map[string]bool)
+	for _, island := range m.Islands {
+		for _, ind := range island.Population {
+			id := ind.Blueprint.Config.Metadata.ModelID
+			if seen[id] {
+				t.Fatalf("model ID %s is used twice", id)
+			}
+			seen[id] = true
+		}
+	}
+
+	if _, err := NewIslandModel(m.Islands[0], m.Islands[1], m.Islands[0]); err == nil {
+		t.Fatal("the same Evolver was accepted twice")
+	}
+}
+
+func TestNewIslandModelRenamesLineage(t *testing.T) {
+	var islands []*Evolver
+	for i := 0; i < 2; i++ {
+		e, err := NewEvolver(newTestNetwork(3), 3, parameterCount, int64(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		islands = append(islands, e)
+	}
+	// Link two initial individuals, as breeding within the initial population would
+	first, second := islands[0].Population[0].Blueprint, islands[0].Population[1].Blueprint
+	first.Config.Metadata.ChildModelIDs = []string{second.Config.Metadata.ModelID}
+	second.Config.Metadata.ParentModelIDs = append(second.Config.Metadata.ParentModelIDs, first.Config.Metadata.ModelID)
+
+	// Building a second island model over the same Evolvers replaces the suffix instead of adding one
+	for round := 0; round < 2; round++ {
+		if _, err := NewIslandModel(islands...); err != nil {
+			t.Fatal(err)
+		}
+		for i, island := range islands {
+			for j, ind := range island.Population {
+				if got, want := ind.Blueprint.Config.Metadata.ModelID, "test_i"+strconv.Itoa(i)+"_g0_"+strconv.Itoa(j); got != want {
+					t.Fatalf("round %d: model ID %s, want %s", round, got, want)
+				}
+			}
+		}
+		if got := first.Config.Metadata.ChildModelIDs; len(got) != 1 || got[0] != second.Config.Metadata.ModelID {
+			t.Fatalf("round %d: child IDs %v, want [%s]", round, got, second.Config.Metadata.ModelID)
+		}
+		if got := second.Config.Metadata.ParentModelIDs; len(got) != 2 || got[0] != "test" || got[1] != first.Config.Metadata.ModelID {
+			t.Fatalf("round %d: parent IDs %v, want [test %s]", round, got, first.Config.Metadata.ModelID)
+		}
+	}
+}
+
+func TestMigrateRing(t *testing.T) {
+	m := newTestIslands(t, 3)
+	emigrants := make([]string, 3)
+	for i, island := range m.Islands {
+		emigrants[i] = island.Population[0].Blueprint.Config.Metadata.ModelID
+	}
+	if err := m.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, island := range m.Islands {
+		source := (i + 2) % 3
+		migrant := island.Population[len(island.Population)-1]
+		metadata := migrant.Blueprint.Config.Metadata
+		if migrant.Evaluated || len(metadata.ParentModelIDs) != 1 || metadata.ParentModelIDs[0] != emigrants[source] {
+			t.Fatalf("island %d received %+v, want a fresh child of %s", i, metadata, emigrants[source])
+		}
+		if got := islandOf(metadata.ModelID); got != strconv.Itoa(i) {
+			t.Errorf("migrant %s into island %d is not named by its receiving island", metadata.ModelID, i)
+		}
+	}
+}
+
+func TestMigrateFullyConnected(t *testing.T) {
+	m := newTestIslands(t, 3)
+	m.Topology = "fully_connected"
+	if err := m.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, island := range m.Islands {
+		// Each island keeps its two best and receives the best of both other islands
+		sources := make(map[string]bool)
+		for _, ind := range island.Population[2:] {
+			parents := ind.Blueprint.Config.Metadata.ParentModelIDs
+			if ind.Evaluated || len(parents) != 1 {
+				t.Fatalf("island %d kept %s instead of a migrant", i, ind.Blueprint.Config.Metadata.ModelID)
+			}
+			sources[islandOf(parents[0])] = true
+		}
+		if len(sources) != 2 || sources[strconv.Itoa(i)] {
+			t.Errorf("island %d received migrants from %v, want both other islands", i, sources)
+		}
+	}
+}
+
+func TestRepeatedShortRunsMigrate(t *testing.T) {
+	m := newTestIslands(t, 2)
+	m.MigrationInterval = 2
+	for i := 0; i < 2; i++ {
+		if _, err := m.Run(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, island := range m.Islands {
+		migrated := false
+		for _, ind := range island.Population {
+			for _, parent := range ind.Blueprint.Config.Metadata.ParentModelIDs {
+				if source := islandOf(parent); source != "" && source != strconv.Itoa(i) {
+					migrated = true
+				}
+			}
+		}
+		if !migrated {
+			t.Errorf("island %d received no migrant after two runs of one generation", i)
+		}
+	}
+}
+
+func TestMigrateRanksByObjectives(t *testing.T) {
+	m := newTestIslands(t, 2)
+	// Minimizing fitness reverses the preference, so the least fit individual emigrates and the fittest
+	// is replaced
+	lowest := Objective{Name: "lowest", Value: func(ind *Individual) float64 { return ind.Fitness }}
+	var weakest, fittest []string
+	for _, island := range m.Islands {
+		island.Objectives = []Objective{lowest}
+		weakest = append(weakest, island.Population[len(island.Population)-1].Blueprint.Config.Metadata.ModelID)
+		fittest = append(fittest, island.Population[0].Blueprint.Config.Metadata.ModelID)
+	}
+	if err := m.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, island := range m.Islands {
+		ids := make(map[string]bool)
+		var parents []string
+		for _, ind := range island.Population {
+			ids[ind.Blueprint.Config.Metadata.ModelID] = true
+			parents = append(parents, ind.Blueprint.Config.Metadata.ParentModelIDs...)
+		}
+		if !ids[weakest[i]] || ids[fittest[i]] {
+			t.Errorf("island %d kept the wrong individuals: %v", i, ids)
+		}
+		if !slices.Contains(parents, weakest[1-i]) {
+			t.Errorf("island %d did not receive %s", i, weakest[1-i])
+		}
+	}
+}
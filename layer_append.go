@@ -3,121 +3,139 @@ package blueprint
 
 import (
 	"fmt"
-	"math/rand"
 	"strconv"
 	// Importing UUID package for neuron IDs
 )
 
-// AppendNewLayerFullConnections adds a new hidden dense layer with fully connected neurons
-func (bp *Blueprint) AppendNewLayerFullConnections(numNewNeurons int) {
-	newLayer := Layer{
-		Neurons:   make(map[string]Neuron),
-		LayerType: "dense",
+// AppendNewLayerFullConnections adds a new hidden dense layer with fully connected neurons. It fails,
+// leaving the network unchanged, when the outputs of the last layer cannot be determined.
+// Weights and biases come from the optional initializer, or a standard normal distribution by default.
+func (bp *Blueprint) AppendNewLayerFullConnections(numNewNeurons int, initializer ...Initializer) error {
+	weightInit := pickInitializer(NormalInitializer{StdDev: 1}, initializer)
+	previousKeys, err := bp.previousLayerKeys()
+	if err != nil {
+		return err
 	}
 
 	// Get the initial highest neuron ID
 	highestID := bp.getHighestNeuronID() + 1 // Start from the next available ID
 
-	for i := 0; i < numNewNeurons; i++ {
-		neuronID := "neuron" + strconv.FormatInt(highestID, 10)
-		newNeuron := Neuron{
-			ActivationType: randomActivationType(),
-			Connections:    make(map[string]Connection),
-			Bias:           rand.NormFloat64(),
-		}
-
-		var previousLayerNeurons map[string]Neuron
-		if len(bp.Config.Layers.Hidden) == 0 {
-			previousLayerNeurons = bp.Config.Layers.Input.Neurons
-		} else {
-			previousLayerNeurons = bp.Config.Layers.Hidden[len(bp.Config.Layers.Hidden)-1].Neurons
-		}
-		for prevNeuronID := range previousLayerNeurons {
-			newNeuron.Connections[prevNeuronID] = Connection{Weight: rand.NormFloat64()}
-		}
-		newLayer.Neurons[neuronID] = newNeuron
-		highestID++ // Increment for the next neuron
-	}
-
+	newLayer := bp.newDenseLayer(numNewNeurons, highestID, previousKeys, weightInit)
 	bp.Config.Layers.Hidden = append(bp.Config.Layers.Hidden, newLayer)
+	return nil
 }
 
-// AppendMultipleLayers appends multiple layers with a specified number of neurons to the network
-func (bp *Blueprint) AppendMultipleLayers(numNewLayers, numNewNeurons int) {
+// AppendMultipleLayers appends multiple layers with a specified number of neurons to the network. It
+// fails like AppendNewLayerFullConnections, before appending any layer.
+// Weights and biases come from the optional initializer, or a standard normal distribution by default.
+func (bp *Blueprint) AppendMultipleLayers(numNewLayers, numNewNeurons int, initializer ...Initializer) error {
+	weightInit := pickInitializer(NormalInitializer{StdDev: 1}, initializer)
+	previousKeys, err := bp.previousLayerKeys()
+	if err != nil {
+		return err
+	}
+
 	// Get the initial highest neuron ID once at the beginning
 	highestID := bp.getHighestNeuronID() + 1 // Start from the next available ID
 
 	for i := 0; i < numNewLayers; i++ {
-		layer := Layer{
-			Neurons:   make(map[string]Neuron),
-			LayerType: "dense",
+		layer := bp.newDenseLayer(numNewNeurons, highestID, previousKeys, weightInit)
+		highestID += int64(numNewNeurons)
+
+		// Append the newly created layer to the hidden layers; the next one reads from its neurons
+		bp.Config.Layers.Hidden = append(bp.Config.Layers.Hidden, layer)
+		previousKeys = sortedKeys(layer.Neurons)
+	}
+	return nil
+}
+
+// newDenseLayer builds a dense layer of numNeurons sequentially numbered neurons, starting at firstID,
+// each connected to every previous key and given a random activation type.
+func (bp *Blueprint) newDenseLayer(numNeurons int, firstID int64, previousKeys []string, weightInit Initializer) Layer {
+	layer := Layer{
+		Neurons:   make(map[string]Neuron),
+		LayerType: "dense",
+	}
+
+	weights := weightInit.Weights(bp.Rand(), numNeurons, len(previousKeys))
+	for i := 0; i < numNeurons; i++ {
+		// Generate a new neuron ID sequentially
+		neuronID := "neuron" + strconv.FormatInt(firstID+int64(i), 10)
+		newNeuron := Neuron{
+			ActivationType: bp.randomActivationType(),
+			Connections:    make(map[string]Connection),
+			Bias:           weightInit.Bias(bp.Rand()),
 		}
-		for j := 0; j < numNewNeurons; j++ {
-			// Generate a new neuron ID sequentially
-			neuronID := "neuron" + strconv.FormatInt(highestID, 10)
-			newNeuron := Neuron{
-				ActivationType: randomActivationType(),
-				Connections:    make(map[string]Connection),
-				Bias:           rand.NormFloat64(),
-			}
-
-			// Set up connections from neurons in the previous layer
-			var previousLayerNeurons map[string]Neuron
-			if len(bp.Config.Layers.Hidden) == 0 {
-				previousLayerNeurons = bp.Config.Layers.Input.Neurons
-			} else {
-				previousLayerNeurons = bp.Config.Layers.Hidden[len(bp.Config.Layers.Hidden)-1].Neurons
-			}
-			for prevNeuronID := range previousLayerNeurons {
-				newNeuron.Connections[prevNeuronID] = Connection{Weight: rand.NormFloat64()}
-			}
-
-			// Add the new neuron to the current layer
-			layer.Neurons[neuronID] = newNeuron
-			highestID++ // Increment the neuron ID for the next neuron
+
+		// Set up connections from the outputs of the previous layer
+		for j, prevNeuronID := range previousKeys {
+			newNeuron.Connections[prevNeuronID] = Connection{Weight: weights[i][j]}
 		}
-		// Append the newly created layer to the hidden layers
-		bp.Config.Layers.Hidden = append(bp.Config.Layers.Hidden, layer)
+		layer.Neurons[neuronID] = newNeuron
 	}
+	return layer
 }
 
-// AppendCNNLayer adds a CNN layer to the network configuration
-func (bp *Blueprint) AppendCNNLayer(filterSize, numFilters, stride, padding int) error {
+// AppendCNNLayer adds a CNN layer to the network configuration.
+// It fails if the previous layer is known to produce something other than an image.
+// Weights and biases come from the optional initializer, or a uniform [0, 1) distribution by default.
+func (bp *Blueprint) AppendCNNLayer(filterSize, numFilters, stride, padding int, initializer ...Initializer) error {
 	if filterSize <= 0 || numFilters <= 0 || stride <= 0 || padding < 0 {
 		return fmt.Errorf("invalid CNN layer parameters")
 	}
+	if shape, err := bp.lastHiddenShape(); err == nil && shape.Kind != "image" {
+		return fmt.Errorf("cannot append CNN layer: %w", &ShapeMismatchError{Expected: "image", Actual: shape.Kind})
+	}
+	weightInit := pickInitializer(UniformInitializer{Low: 0, High: 1}, initializer)
 
+	kernels := weightInit.Weights(bp.Rand(), numFilters, filterSize*filterSize)
 	filters := make([]Filter, numFilters)
 	for i := 0; i < numFilters; i++ {
+		weights := make([][]float64, filterSize)
+		for r := range weights {
+			weights[r] = kernels[i][r*filterSize : (r+1)*filterSize]
+		}
 		filters[i] = Filter{
-			Weights: Random2DSlice(filterSize, filterSize),
-			Bias:    rand.Float64(),
+			Weights: weights,
+			Bias:    weightInit.Bias(bp.Rand()),
 		}
 	}
 
 	newLayer := Layer{
-		LayerType: "conv",
-		Filters:   filters,
-		Stride:    stride,
-		Padding:   padding,
+		LayerType:      "conv",
+		Filters:        filters,
+		Stride:         stride,
+		Padding:        padding,
+		ActivationType: "relu",
 	}
 	bp.Config.Layers.Hidden = append(bp.Config.Layers.Hidden, newLayer)
 	return nil
 }
 
-// AppendLSTMLayer appends an LSTM layer to the network configuration
-func (bp *Blueprint) AppendLSTMLayer() {
-	lstmLayer := Layer{
-		LayerType: "lstm",
-		LSTMCells: []LSTMCell{
-			{
-				InputWeights:  RandomSlice(10),
-				ForgetWeights: RandomSlice(10),
-				OutputWeights: RandomSlice(10),
-				CellWeights:   RandomSlice(10),
-				Bias:          rand.Float64(),
-			},
-		},
+// AppendLSTMLayer appends an LSTM layer with a single cell to the network configuration.
+// The weight vectors match the feature width of the previous layer, or 10 when it cannot be inferred.
+// Weights and biases come from the optional initializer, or a uniform [0, 1) distribution by default.
+func (bp *Blueprint) AppendLSTMLayer(initializer ...Initializer) {
+	bp.AppendLSTMLayerWithCells(1, initializer...)
+}
+
+// AppendLSTMLayerWithCells appends an LSTM layer with numCells cells, initialized like AppendLSTMLayer.
+func (bp *Blueprint) AppendLSTMLayerWithCells(numCells int, initializer ...Initializer) {
+	weightInit := pickInitializer(UniformInitializer{Low: 0, High: 1}, initializer)
+	width := 10
+	if shape, err := bp.lastHiddenShape(); err == nil && shape.featureWidth() > 0 {
+		width = shape.featureWidth()
+	}
+
+	lstmLayer := Layer{LayerType: "lstm"}
+	for i := 0; i < numCells; i++ {
+		lstmLayer.LSTMCells = append(lstmLayer.LSTMCells, LSTMCell{
+			InputWeights:  weightInit.Weights(bp.Rand(), 1, width)[0],
+			ForgetWeights: weightInit.Weights(bp.Rand(), 1, width)[0],
+			OutputWeights: weightInit.Weights(bp.Rand(), 1, width)[0],
+			CellWeights:   weightInit.Weights(bp.Rand(), 1, width)[0],
+			Bias:          weightInit.Bias(bp.Rand()),
+		})
 	}
 	bp.Config.Layers.Hidden = append(bp.Config.Layers.Hidden, lstmLayer)
 }
@@ -0,0 +1,153 @@
+// blueprint/loss.go
+package blueprint
+
+import (
+	"fmt"
+	"math"
+)
+
+// lossEpsilon keeps probabilities away from 0 and 1 before taking logarithms.
+const lossEpsilon = 1e-12
+
+// Loss measures how far the network outputs are from the targets.
+// Compute returns the loss value and its gradient with respect to each output produced by Feedforward.
+// Only outputs that have a matching target contribute to the loss.
+type Loss interface {
+	Name() string
+	Compute(outputs, targets map[string]float64) (float64, map[string]float64, error)
+}
+
+// NewLoss returns the loss function registered under the given name.
+func NewLoss(name string) (Loss, error) {
+	switch name {
+	case "mse":
+		return MeanSquaredError{}, nil
+	case "binary_crossentropy":
+		return BinaryCrossEntropy{}, nil
+	case "categorical_crossentropy":
+		return CategoricalCrossEntropy{}, nil
+	case "huber":
+		return Huber{Delta: 1.0}, nil
+	case "hinge":
+		return Hinge{}, nil
+	default:
+		return nil, fmt.Errorf("unknown loss function: %s", name)
+	}
+}
+
+// MeanSquaredError averages the squared difference between outputs and targets.
+type MeanSquaredError struct{}
+
+// Name returns the registered name of the loss.
+func (MeanSquaredError) Name() string { return "mse" }
+
+// Compute returns the mean squared error and its gradient.
+func (MeanSquaredError) Compute(outputs, targets map[string]float64) (float64, map[string]float64, error) {
+	return computeLoss(outputs, targets, func(output, target float64) (float64, float64) {
+		diff := output - target
+		return diff * diff, 2 * diff
+	}, true)
+}
+
+// BinaryCrossEntropy averages the log loss of independent probabilities, typically sigmoid outputs.
+type BinaryCrossEntropy struct{}
+
+// Name returns the registered name of the loss.
+func (BinaryCrossEntropy) Name() string { return "binary_crossentropy" }
+
+// Compute returns the binary cross-entropy and its gradient.
+func (BinaryCrossEntropy) Compute(outputs, targets map[string]float64) (float64, map[string]float64, error) {
+	return computeLoss(outputs, targets, func(output, target float64) (float64, float64) {
+		p := clampProbability(output)
+		value := -(target*math.Log(p) + (1-target)*math.Log(1-p))
+		return value, (p - target) / (p * (1 - p))
+	}, true)
+}
+
+// CategoricalCrossEntropy sums the log loss over a probability distribution, typically softmax outputs
+// paired with one-hot targets.
+type CategoricalCrossEntropy struct{}
+
+// Name returns the registered name of the loss.
+func (CategoricalCrossEntropy) Name() string { return "categorical_crossentropy" }
+
+// Compute returns the categorical cross-entropy and its gradient.
+func (CategoricalCrossEntropy) Compute(outputs, targets map[string]float64) (float64, map[string]float64, error) {
+	return computeLoss(outputs, targets, func(output, target float64) (float64, float64) {
+		p := clampProbability(output)
+		return -target * math.Log(p), -target / p
+	}, false)
+}
+
+// Huber is quadratic for errors smaller than Delta and linear beyond it, making it robust to outliers.
+type Huber struct {
+	Delta float64
+}
+
+// Name returns the registered name of the loss.
+func (Huber) Name() string { return "huber" }
+
+// Compute returns the Huber loss and its gradient.
+func (h Huber) Compute(outputs, targets map[string]float64) (float64, map[string]float64, error) {
+	if h.Delta <= 0 {
+		return 0, nil, fmt.Errorf("huber delta must be positive, got %v", h.Delta)
+	}
+	return computeLoss(outputs, targets, func(output, target float64) (float64, float64) {
+		diff := output - target
+		if math.Abs(diff) <= h.Delta {
+			return 0.5 * diff * diff, diff
+		}
+		return h.Delta * (math.Abs(diff) - 0.5*h.Delta), h.Delta * math.Copysign(1, diff)
+	}, true)
+}
+
+// Hinge is the margin loss used for maximum-margin classifiers. Targets are expected to be -1 or 1;
+// a target of 0 is treated as -1 so one-hot labels can be used directly.
+type Hinge struct{}
+
+// Name returns the registered name of the loss.
+func (Hinge) Name() string { return "hinge" }
+
+// Compute returns the hinge loss and its gradient.
+func (Hinge) Compute(outputs, targets map[string]float64) (float64, map[string]float64, error) {
+	return computeLoss(outputs, targets, func(output, target float64) (float64, float64) {
+		if target == 0 {
+			target = -1
+		}
+		margin := 1 - target*output
+		if margin <= 0 {
+			return 0, 0
+		}
+		return margin, -target
+	}, true)
+}
+
+// computeLoss applies a per-output loss term to every target, optionally averaging over the targets.
+func computeLoss(outputs, targets map[string]float64, term func(output, target float64) (float64, float64), mean bool) (float64, map[string]float64, error) {
+	if len(targets) == 0 {
+		return 0, nil, fmt.Errorf("no target values provided")
+	}
+
+	scale := 1.0
+	if mean {
+		scale = 1 / float64(len(targets))
+	}
+
+	loss := 0.0
+	grad := make(map[string]float64, len(targets))
+	for id, target := range targets {
+		output, ok := outputs[id]
+		if !ok {
+			return 0, nil, fmt.Errorf("target %q does not match any output neuron", id)
+		}
+		value, derivative := term(output, target)
+		loss += value * scale
+		grad[id] = derivative * scale
+	}
+	return loss, grad, nil
+}
+
+// clampProbability keeps p inside (0, 1) so logarithms and divisions stay finite.
+func clampProbability(p float64) float64 {
+	return math.Min(math.Max(p, lossEpsilon), 1-lossEpsilon)
+}
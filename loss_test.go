@@ -0,0 +1,76 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLossValues(t *testing.T) {
+	tests := []struct {
+		loss             Loss
+		outputs, targets map[string]float64
+		want             float64
+	}{
+		{MeanSquaredError{}, map[string]float64{"a": 0.8, "b": 0.3}, map[string]float64{"a": 1, "b": 0}, (0.04 + 0.09) / 2},
+		{BinaryCrossEntropy{}, map[string]float64{"a": 0.8, "b": 0.3}, map[string]float64{"a": 1, "b": 0}, -(math.Log(0.8) + math.Log(0.7)) / 2},
+		{CategoricalCrossEntropy{}, map[string]float64{"a": 0.7, "b": 0.2, "c": 0.1}, map[string]float64{"a": 1, "b": 0, "c": 0}, -math.Log(0.7)},
+		{Huber{Delta: 0.25}, map[string]float64{"a": 0.8, "b": 0.3}, map[string]float64{"a": 1, "b": 0}, (0.5*0.04 + 0.25*(0.3-0.125)) / 2},
+		{Hinge{}, map[string]float64{"a": 0.5, "b": 0.4, "c": 2}, map[string]float64{"a": 1, "b": 0, "c": 1}, (0.5 + 1.4 + 0) / 3},
+	}
+	for _, tt := range tests {
+		got, _, err := tt.loss.Compute(tt.outputs, tt.targets)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.loss.Name(), err)
+		}
+		if math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("%s: loss %v, want %v", tt.loss.Name(), got, tt.want)
+		}
+	}
+}
+
+func TestLossGradients(t *testing.T) {
+	outputs := map[string]float64{"a": 0.62, "b": 0.27, "c": 0.11}
+	targets := map[string]float64{"a": 1, "b": 0, "c": 0}
+	for _, name := range []string{"mse", "binary_crossentropy", "categorical_crossentropy", "huber", "hinge"} {
+		loss, err := NewLoss(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loss.Name() != name {
+			t.Errorf("NewLoss(%q) returned %s", name, loss.Name())
+		}
+		_, grad, err := loss.Compute(outputs, targets)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		const h = 1e-6
+		for id := range outputs {
+			shifted := func(delta float64) float64 {
+				moved := make(map[string]float64, len(outputs))
+				for k, v := range outputs {
+					moved[k] = v
+				}
+				moved[id] += delta
+				value, _, err := loss.Compute(moved, targets)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return value
+			}
+			numeric := (shifted(h) - shifted(-h)) / (2 * h)
+			if math.Abs(numeric-grad[id]) > 1e-6*math.Max(1, math.Abs(numeric)) {
+				t.Errorf("%s: gradient for %s is %v, finite differences give %v", name, id, grad[id], numeric)
+			}
+		}
+	}
+}
+
+func TestNewLossRejectsUnknownNames(t *testing.T) {
+	if _, err := NewLoss("cosine"); err == nil {
+		t.Fatal("unknown loss name accepted")
+	}
+	if _, _, err := (Huber{}).Compute(map[string]float64{"a": 1}, map[string]float64{"a": 0}); err == nil {
+		t.Fatal("Huber with zero delta accepted")
+	}
+}
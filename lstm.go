@@ -2,55 +2,85 @@ package blueprint
 
 import "strconv"
 
-func (bp *Blueprint) processLSTMLayer(layer Layer, inputData interface{}) interface{} {
+func (bp *Blueprint) processLSTMLayer(layer Layer, inputData interface{}) (interface{}, error) {
+	trace, output := bp.lstmForward(layer, inputData)
+	if trace == nil {
+		return nil, shapeMismatch("[][]float64 sequence or map[string]float64", inputData)
+	}
+	return output, nil
+}
+
+// lstmTrace records the gate activations and cell states of every time step of an LSTM layer.
+type lstmTrace struct {
+	sequence  [][]float64
+	inputKeys []string // Set when a single time step was built from a map, in the order used
+	steps     []lstmStep
+}
+
+// lstmStep holds the per-cell values computed at one time step.
+type lstmStep struct {
+	inputGate     []float64
+	forgetGate    []float64
+	outputGate    []float64
+	cellCandidate []float64
+	prevCellState []float64
+	cellState     []float64
+}
+
+// lstmForward runs the LSTM cells over the input sequence and returns the final hidden state as
+// "lstm%d" keys. It returns a nil trace when the input is neither a sequence nor a map.
+func (bp *Blueprint) lstmForward(layer Layer, inputData interface{}) (*lstmTrace, map[string]float64) {
 	// inputData is expected to be [][]float64 (sequence) or map[string]float64 (single time step)
-	var sequence [][]float64
+	trace := &lstmTrace{}
 
 	switch v := inputData.(type) {
 	case [][]float64:
-		sequence = v
+		trace.sequence = v
 	case map[string]float64:
-		// Convert map to []float64
-		inputSlice := make([]float64, len(v))
-		i := 0
-		for _, val := range v {
-			inputSlice[i] = val
-			i++
+		// Convert map to []float64 in a stable key order
+		trace.inputKeys = sortedKeys(v)
+		inputSlice := make([]float64, len(trace.inputKeys))
+		for i, key := range trace.inputKeys {
+			inputSlice[i] = v[key]
 		}
-		sequence = [][]float64{inputSlice}
+		trace.sequence = [][]float64{inputSlice}
 	default:
-		// Handle error
-		return nil
+		return nil, nil
 	}
 
 	// Initialize hidden state and cell state
-	var hiddenState []float64
-	var cellState []float64
-
 	// Assuming all LSTM cells have the same dimensions
 	numCells := len(layer.LSTMCells)
-	hiddenState = make([]float64, numCells)
-	cellState = make([]float64, numCells)
+	hiddenState := make([]float64, numCells)
+	cellState := make([]float64, numCells)
 
-	for _, timeStepInput := range sequence {
+	for _, timeStepInput := range trace.sequence {
 		// For each LSTM cell, compute the new hidden state and cell state
+		step := lstmStep{
+			inputGate:     make([]float64, numCells),
+			forgetGate:    make([]float64, numCells),
+			outputGate:    make([]float64, numCells),
+			cellCandidate: make([]float64, numCells),
+			prevCellState: cellState,
+			cellState:     make([]float64, numCells),
+		}
 		newHiddenState := make([]float64, numCells)
-		newCellState := make([]float64, numCells)
 
 		for i, cell := range layer.LSTMCells {
 			// Compute input gate, forget gate, output gate, and cell candidate
 			// Assuming weights and inputs are compatible
-			inputGate := bp.sigmoid(bp.dotProduct(cell.InputWeights, timeStepInput) + cell.Bias)
-			forgetGate := bp.sigmoid(bp.dotProduct(cell.ForgetWeights, timeStepInput) + cell.Bias)
-			outputGate := bp.sigmoid(bp.dotProduct(cell.OutputWeights, timeStepInput) + cell.Bias)
-			cellCandidate := bp.tanh(bp.dotProduct(cell.CellWeights, timeStepInput) + cell.Bias)
+			step.inputGate[i] = bp.sigmoid(bp.dotProduct(cell.InputWeights, timeStepInput) + cell.Bias)
+			step.forgetGate[i] = bp.sigmoid(bp.dotProduct(cell.ForgetWeights, timeStepInput) + cell.Bias)
+			step.outputGate[i] = bp.sigmoid(bp.dotProduct(cell.OutputWeights, timeStepInput) + cell.Bias)
+			step.cellCandidate[i] = bp.tanh(bp.dotProduct(cell.CellWeights, timeStepInput) + cell.Bias)
 
-			newCellState[i] = forgetGate*cellState[i] + inputGate*cellCandidate
-			newHiddenState[i] = outputGate * bp.tanh(newCellState[i])
+			step.cellState[i] = step.forgetGate[i]*cellState[i] + step.inputGate[i]*step.cellCandidate[i]
+			newHiddenState[i] = step.outputGate[i] * bp.tanh(step.cellState[i])
 		}
 
+		trace.steps = append(trace.steps, step)
 		hiddenState = newHiddenState
-		cellState = newCellState
+		cellState = step.cellState
 	}
 
 	// Return the final hidden state as a map[string]float64
@@ -59,5 +89,83 @@ func (bp *Blueprint) processLSTMLayer(layer Layer, inputData interface{}) interf
 		output["lstm"+strconv.Itoa(i)] = value
 	}
 
-	return output
+	return trace, output
+}
+
+// lstmBackward performs truncated backpropagation through time from the final hidden state. Gradients
+// flow back through at most bp.BPTTWindow time steps, or the whole sequence when the window is zero.
+// The input gradient is a map keyed like the input map, or a [][]float64 for sequence inputs.
+func (bp *Blueprint) lstmBackward(layer Layer, trace *lstmTrace, gradOut map[string]float64) ([]LSTMCellGradient, interface{}) {
+	cellGrads := make([]LSTMCellGradient, len(layer.LSTMCells))
+	inputGrads := make([][]float64, len(trace.sequence))
+	for t, x := range trace.sequence {
+		inputGrads[t] = make([]float64, len(x))
+	}
+
+	numSteps := len(trace.steps)
+	firstStep := 0
+	if bp.BPTTWindow > 0 && numSteps > bp.BPTTWindow {
+		firstStep = numSteps - bp.BPTTWindow
+	}
+
+	for i, cell := range layer.LSTMCells {
+		cellGrad := LSTMCellGradient{
+			InputWeights:  make([]float64, len(cell.InputWeights)),
+			ForgetWeights: make([]float64, len(cell.ForgetWeights)),
+			OutputWeights: make([]float64, len(cell.OutputWeights)),
+			CellWeights:   make([]float64, len(cell.CellWeights)),
+		}
+		if numSteps == 0 {
+			cellGrads[i] = cellGrad
+			continue
+		}
+
+		// Only the final hidden state leaves the layer, so the output gate is used once
+		last := trace.steps[numSteps-1]
+		gradHidden := gradOut["lstm"+strconv.Itoa(i)]
+		tanhCell := bp.tanh(last.cellState[i])
+		gradCell := gradHidden * last.outputGate[i] * (1 - tanhCell*tanhCell)
+		gradOutputGate := gradHidden * tanhCell
+
+		for t := numSteps - 1; t >= firstStep; t-- {
+			step := trace.steps[t]
+			x := trace.sequence[t]
+
+			gradInputPre := gradCell * step.cellCandidate[i] * step.inputGate[i] * (1 - step.inputGate[i])
+			gradForgetPre := gradCell * step.prevCellState[i] * step.forgetGate[i] * (1 - step.forgetGate[i])
+			gradCandidatePre := gradCell * step.inputGate[i] * (1 - step.cellCandidate[i]*step.cellCandidate[i])
+			gradOutputPre := 0.0
+			if t == numSteps-1 {
+				gradOutputPre = gradOutputGate * step.outputGate[i] * (1 - step.outputGate[i])
+			}
+
+			// dotProduct treats mismatched lengths as zero, so those weights receive no gradient
+			accumulate := func(weightGrads, weights []float64, gradPre float64) {
+				if len(weights) != len(x) {
+					return
+				}
+				for k := range x {
+					weightGrads[k] += gradPre * x[k]
+					inputGrads[t][k] += gradPre * weights[k]
+				}
+			}
+			accumulate(cellGrad.InputWeights, cell.InputWeights, gradInputPre)
+			accumulate(cellGrad.ForgetWeights, cell.ForgetWeights, gradForgetPre)
+			accumulate(cellGrad.OutputWeights, cell.OutputWeights, gradOutputPre)
+			accumulate(cellGrad.CellWeights, cell.CellWeights, gradCandidatePre)
+			cellGrad.Bias += gradInputPre + gradForgetPre + gradOutputPre + gradCandidatePre
+
+			gradCell *= step.forgetGate[i]
+		}
+		cellGrads[i] = cellGrad
+	}
+
+	if trace.inputKeys != nil {
+		inputGrad := make(map[string]float64, len(trace.inputKeys))
+		for k, key := range trace.inputKeys {
+			inputGrad[key] = inputGrads[0][k]
+		}
+		return cellGrads, inputGrad
+	}
+	return cellGrads, inputGrads
 }
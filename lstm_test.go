@@ -0,0 +1,127 @@
+package blueprint
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+// wave returns n deterministic values in [-scale, scale] that differ with phase.
+func wave(n int, phase, scale float64) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = scale * math.Sin(1.7*float64(i)+phase)
+	}
+	return values
+}
+
+// newLSTMTestNetwork builds a sequence network of an LSTM layer, a tanh dense layer and a second LSTM
+// layer feeding two sigmoid outputs, and returns it with a four-step input sequence.
+func newLSTMTestNetwork() (*Blueprint, map[string]interface{}) {
+	bp := NewBlueprint(&NetworkConfig{})
+	bp.Config.Layers.Input = Layer{LayerType: "lstm", Shape: []int{4, 3}}
+
+	first := Layer{LayerType: "lstm"}
+	for c := 0; c < 3; c++ {
+		phase := float64(c)
+		first.LSTMCells = append(first.LSTMCells, LSTMCell{
+			InputWeights:  wave(3, phase, 0.5),
+			ForgetWeights: wave(3, phase+1, 0.5),
+			OutputWeights: wave(3, phase+2, 0.5),
+			CellWeights:   wave(3, phase+3, 0.5),
+			Bias:          0.1,
+		})
+	}
+	dense := Layer{LayerType: "dense", Neurons: make(map[string]Neuron)}
+	for n := 0; n < 4; n++ {
+		weights := wave(3, float64(n)+0.3, 1)
+		connections := make(map[string]Connection)
+		for k, weight := range weights {
+			connections["lstm"+strconv.Itoa(k)] = Connection{Weight: weight}
+		}
+		dense.Neurons["neuron"+strconv.Itoa(n+10)] = Neuron{ActivationType: "tanh", Connections: connections}
+	}
+	second := Layer{LayerType: "lstm"}
+	for c := 0; c < 2; c++ {
+		phase := float64(c) + 0.6
+		second.LSTMCells = append(second.LSTMCells, LSTMCell{
+			InputWeights:  wave(4, phase, 0.5),
+			ForgetWeights: wave(4, phase+1, 0.5),
+			OutputWeights: wave(4, phase+2, 0.5),
+			CellWeights:   wave(4, phase+3, 0.5),
+			Bias:          -0.1,
+		})
+	}
+	bp.Config.Layers.Hidden = []Layer{first, dense, second}
+
+	output := Layer{LayerType: "dense", Neurons: make(map[string]Neuron)}
+	for n := 0; n < 2; n++ {
+		weights := wave(2, float64(n)+0.9, 1)
+		output.Neurons["output"+strconv.Itoa(n)] = Neuron{
+			ActivationType: "sigmoid",
+			Connections:    map[string]Connection{"lstm0": {Weight: weights[0]}, "lstm1": {Weight: weights[1]}},
+		}
+	}
+	bp.Config.Layers.Output = output
+
+	sequence := make([][]float64, 4)
+	for t := range sequence {
+		sequence[t] = wave(3, float64(t)*0.8, 0.5)
+	}
+	return bp, map[string]interface{}{"sequence": sequence}
+}
+
+func TestComputeGradientsLSTM(t *testing.T) {
+	bp, input := newLSTMTestNetwork()
+	checkGradients(t, bp, input, map[string]float64{"output0": 1, "output1": 0}, MeanSquaredError{})
+}
+
+func TestBPTTWindow(t *testing.T) {
+	bp, input := newLSTMTestNetwork()
+	targets := map[string]float64{"output0": 1, "output1": 0}
+	full, _, err := bp.ComputeGradients(input, targets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A window covering the whole sequence is the same as no truncation
+	bp.BPTTWindow = 4
+	covering, _, err := bp.ComputeGradients(input, targets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for c := range full.Layers[0].LSTMCells {
+		got, want := covering.Layers[0].LSTMCells[c], full.Layers[0].LSTMCells[c]
+		if !closeVectors(got.ForgetWeights, want.ForgetWeights) || math.Abs(got.Bias-want.Bias) > 1e-12 {
+			t.Fatalf("cell %d: a window covering the sequence changed the gradients", c)
+		}
+	}
+
+	// A one-step window only sees the last input, so the output gate gradient is unchanged while the
+	// gates used at every step lose the contributions of earlier steps
+	bp.BPTTWindow = 1
+	truncated, _, err := bp.ComputeGradients(input, targets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, want := truncated.Layers[0].LSTMCells[0], full.Layers[0].LSTMCells[0]
+	if !closeVectors(got.OutputWeights, want.OutputWeights) {
+		t.Errorf("output gate gradients %v, want %v", got.OutputWeights, want.OutputWeights)
+	}
+	if closeVectors(got.ForgetWeights, want.ForgetWeights) {
+		t.Error("truncation did not change the forget gate gradients")
+	}
+}
+
+// closeVectors reports whether a and b have the same length and agree to within 1e-12.
+func closeVectors(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 1e-12 {
+			return false
+		}
+	}
+	return true
+}
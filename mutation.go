@@ -4,45 +4,351 @@ package blueprint
 import (
 	"fmt"
 	"math/rand"
+	"slices"
 )
 
-// ApplySingleMutation applies a single mutation to the model configuration.
-func (bp *Blueprint) ApplySingleMutation(mutationTypes []string, neuronRange [2]int, layerRange [2]int) {
-	// Randomly select a mutation type from the list
-	mutationType := mutationTypes[rand.Intn(len(mutationTypes))]
+// MutationTypes lists every mutation type understood by ApplyMutation.
+var MutationTypes = []string{
+	"AppendNewLayer", "AppendMultipleLayers", "AppendCNNAndDenseLayer", "AppendLSTMLayer",
+	"RemoveLayer", "RemoveNeuron", "PruneConnection", "InsertNeuron", "WidenLayer",
+	"MutateNeuronActivation", "MutateLayerActivation",
+	"PerturbWeights", "ResetWeights", "MutateFilters", "MutateLSTMCells",
+}
+
+// MutationConfig controls which mutations ApplyMutation picks and the parameters they use. Ranges are
+// inclusive [min, max] pairs.
+type MutationConfig struct {
+	Probabilities map[string]float64 `json:"probabilities"` // Relative weight of each mutation type
+
+	NeuronRange      [2]int `json:"neuronRange"`      // Neurons per new dense layer (at least its inputs in identity mode) or added by WidenLayer
+	LayerRange       [2]int `json:"layerRange"`       // Layers added by AppendMultipleLayers
+	FilterCountRange [2]int `json:"filterCountRange"` // Filters per new conv layer
+	FilterSizeRange  [2]int `json:"filterSizeRange"`  // Kernel size of new conv layers
+	LSTMCellRange    [2]int `json:"lstmCellRange"`    // Cells per new LSTM layer
+
+	WeightMutationRate   float64 `json:"weightMutationRate"`   // Used by PerturbWeights, MutateFilters and MutateLSTMCells
+	WeightMutationStdDev float64 `json:"weightMutationStdDev"` // Used by PerturbWeights, MutateFilters and MutateLSTMCells
+	WeightResetRate      float64 `json:"weightResetRate"`      // Used by ResetWeights
+
+	Activations []string `json:"activations,omitempty"` // Allow-list for activation mutations; empty allows all supported
+	Initializer string   `json:"initializer,omitempty"` // NewInitializer name for new weights; empty keeps each operation's default
+
+	// InsertionMode is "random" (the default) to append dense layers of exactly the drawn number of randomly
+	// initialized neurons, which a dense output layer reads with zero weight in place of its old inputs, or
+	// "identity" to append layers built with InsertIdentityLayer and WidenLayer, which keep the network's
+	// outputs.
+	InsertionMode string `json:"insertionMode,omitempty"`
+}
+
+// DefaultMutationConfig returns a configuration that mostly tunes weights, grows and shrinks dense
+// layers and occasionally changes activations or adds LSTM layers.
+func DefaultMutationConfig() MutationConfig {
+	return MutationConfig{
+		Probabilities: map[string]float64{
+			"AppendNewLayer":         1,
+			"AppendMultipleLayers":   0.5,
+			"AppendLSTMLayer":        0.25,
+			"RemoveLayer":            0.5,
+			"RemoveNeuron":           0.5,
+			"PruneConnection":        0.5,
+			"InsertNeuron":           1,
+			"WidenLayer":             0.5,
+			"MutateNeuronActivation": 0.5,
+			"MutateLayerActivation":  0.25,
+			"PerturbWeights":         2,
+			"ResetWeights":           0.5,
+			"MutateFilters":          0.25,
+			"MutateLSTMCells":        0.25,
+		},
+		NeuronRange:          [2]int{1, 8},
+		LayerRange:           [2]int{1, 2},
+		FilterCountRange:     [2]int{1, 4},
+		FilterSizeRange:      [2]int{3, 5},
+		LSTMCellRange:        [2]int{1, 4},
+		WeightMutationRate:   DefaultWeightMutationRate,
+		WeightMutationStdDev: DefaultWeightMutationStdDev,
+		WeightResetRate:      DefaultWeightResetRate,
+		InsertionMode:        "random",
+	}
+}
+
+// Validate checks that every probability names a known mutation type and at least one is positive, that
+// every range is positive and ordered, that the rates lie in [0, 1] and that the activations and the
+// initializer exist.
+func (cfg MutationConfig) Validate() error {
+	total := 0.0
+	for _, mutationType := range sortedKeys(cfg.Probabilities) {
+		p := cfg.Probabilities[mutationType]
+		if !slices.Contains(MutationTypes, mutationType) {
+			return fmt.Errorf("unknown mutation type %q", mutationType)
+		}
+		if p < 0 {
+			return fmt.Errorf("probability of %s must not be negative, got %v", mutationType, p)
+		}
+		total += p
+	}
+	if total <= 0 {
+		return fmt.Errorf("at least one mutation type needs a positive probability")
+	}
+
+	ranges := []struct {
+		name string
+		r    [2]int
+	}{
+		{"neuron range", cfg.NeuronRange},
+		{"layer range", cfg.LayerRange},
+		{"filter count range", cfg.FilterCountRange},
+		{"filter size range", cfg.FilterSizeRange},
+		{"LSTM cell range", cfg.LSTMCellRange},
+	}
+	for _, rg := range ranges {
+		if rg.r[0] <= 0 || rg.r[1] < rg.r[0] {
+			return fmt.Errorf("%s must satisfy 0 < min <= max, got %v", rg.name, rg.r)
+		}
+	}
+
+	if cfg.WeightMutationRate < 0 || cfg.WeightMutationRate > 1 {
+		return fmt.Errorf("weight mutation rate must be in [0, 1], got %v", cfg.WeightMutationRate)
+	}
+	if cfg.WeightResetRate < 0 || cfg.WeightResetRate > 1 {
+		return fmt.Errorf("weight reset rate must be in [0, 1], got %v", cfg.WeightResetRate)
+	}
+	if cfg.WeightMutationStdDev < 0 {
+		return fmt.Errorf("weight mutation standard deviation must not be negative, got %v", cfg.WeightMutationStdDev)
+	}
+
+	if cfg.InsertionMode != "" && cfg.InsertionMode != "random" && cfg.InsertionMode != "identity" {
+		return fmt.Errorf("insertion mode must be \"random\" or \"identity\", got %q", cfg.InsertionMode)
+	}
+	for _, activation := range cfg.Activations {
+		if !slices.Contains(SupportedActivations, activation) {
+			return fmt.Errorf("unknown activation type %q", activation)
+		}
+	}
+	if _, err := cfg.initializer(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// initializer returns the configured initializer, or nil to use each operation's default.
+func (cfg MutationConfig) initializer() (Initializer, error) {
+	if cfg.Initializer == "" {
+		return nil, nil
+	}
+	return NewInitializer(cfg.Initializer)
+}
+
+// pick draws a mutation type with probability proportional to its weight.
+func (cfg MutationConfig) pick(rng *rand.Rand) string {
+	types := sortedKeys(cfg.Probabilities)
+	total := 0.0
+	for _, mutationType := range types {
+		total += cfg.Probabilities[mutationType]
+	}
+	r := rng.Float64() * total
+	for _, mutationType := range types {
+		r -= cfg.Probabilities[mutationType]
+		if r < 0 {
+			return mutationType
+		}
+	}
+	// Rounding can leave r at zero; fall back to the last type that can be picked
+	for i := len(types) - 1; i >= 0; i-- {
+		if cfg.Probabilities[types[i]] > 0 {
+			return types[i]
+		}
+	}
+	return ""
+}
+
+// MutationRecord describes a mutation applied by ApplyMutation. Fields that do not apply to the mutation
+// type are left at their zero values.
+type MutationRecord struct {
+	Type       string `json:"type"`
+	LayerIndex int    `json:"layerIndex"`           // Layer touched, -1 when the mutation is not tied to one layer
+	LayerType  string `json:"layerType,omitempty"`  // Type of the layer added, removed or changed
+	NeuronID   string `json:"neuronID,omitempty"`   // Neuron inserted, removed or changed
+	InputID    string `json:"inputID,omitempty"`    // Input of a pruned connection
+	Activation string `json:"activation,omitempty"` // New activation type
+
+	LayersAdded int `json:"layersAdded,omitempty"`
+	Units       int `json:"units,omitempty"`      // Neurons in each added dense layer, or filters or cells in an added conv or LSTM layer
+	DenseUnits  int `json:"denseUnits,omitempty"` // Neurons in the dense layer appended after a conv or LSTM layer
+	FilterSize  int `json:"filterSize,omitempty"` // Kernel size of an added conv layer
+
+	ParametersChanged int `json:"parametersChanged,omitempty"` // Weights and biases changed by weight mutations
+
+	NeuronsBefore int64 `json:"neuronsBefore"`
+	NeuronsAfter  int64 `json:"neuronsAfter"`
+	LayersBefore  int64 `json:"layersBefore"`
+	LayersAfter   int64 `json:"layersAfter"`
+}
+
+// ApplyMutation validates cfg, applies one mutation drawn according to its probabilities and returns a
+// record of what changed. After a structural mutation the output layer is reconnected with
+// ReconnectOutputLayer, keeping its learned weights, and the metadata counts are updated. Dense layers
+// appended in identity mode pass on the values they receive, so the output layer keeps reading them with
+// its old weights; in random mode the output layer reads the new neurons with zero weight instead, and
+// conv and LSTM appends replace its inputs, and with them its weights. When the drawn mutation
+// cannot be applied, for example removing a layer from a network with a single hidden layer, the network
+// is left unchanged and the error says why.
+func (bp *Blueprint) ApplyMutation(cfg MutationConfig) (MutationRecord, error) {
+	if err := cfg.Validate(); err != nil {
+		return MutationRecord{}, fmt.Errorf("invalid mutation config: %w", err)
+	}
+	weightInit, _ := cfg.initializer()
+	rng := bp.Rand()
+
+	rec := MutationRecord{
+		Type:          cfg.pick(rng),
+		LayerIndex:    -1,
+		NeuronsBefore: bp.countNeurons(),
+		LayersBefore:  bp.countLayers(),
+	}
+	// Without known previous outputs every current output counts as new when reconnecting
+	previousKeys, _ := bp.previousLayerKeys()
+	inRange := func(r [2]int) int {
+		return rng.Intn(r[1]-r[0]+1) + r[0]
+	}
 
-	// Apply the mutation based on the selected type
-	switch mutationType {
+	var err error
+	structural := true
+	switch rec.Type {
 	case "AppendNewLayer":
-		numNewNeuronsOrFilters := rand.Intn(neuronRange[1]-neuronRange[0]+1) + neuronRange[0]
-		bp.AppendNewLayerFullConnections(numNewNeuronsOrFilters)
+		rec.LayerIndex, rec.LayerType = len(bp.Config.Layers.Hidden), "dense"
+		rec.LayersAdded, rec.Units = 1, inRange(cfg.NeuronRange)
+		if cfg.InsertionMode == "identity" {
+			rec.Units, err = bp.appendIdentityLayer(rec.Units)
+		} else {
+			rec.Units, err = bp.appendRandomLayer(rec.Units, weightInit)
+		}
 
 	case "AppendMultipleLayers":
-		numNewLayers := rand.Intn(layerRange[1]-layerRange[0]+1) + layerRange[0]
-		numNewNeuronsOrFilters := rand.Intn(neuronRange[1]-neuronRange[0]+1) + neuronRange[0]
-		bp.AppendMultipleLayers(numNewLayers, numNewNeuronsOrFilters)
+		rec.LayerIndex, rec.LayerType = len(bp.Config.Layers.Hidden), "dense"
+		rec.LayersAdded, rec.Units = inRange(cfg.LayerRange), inRange(cfg.NeuronRange)
+		hidden, output := bp.Config.Layers.Hidden, copyLayer(bp.Config.Layers.Output)
+		units := rec.Units
+		for i := 0; i < rec.LayersAdded && err == nil; i++ {
+			if cfg.InsertionMode == "identity" {
+				rec.Units, err = bp.appendIdentityLayer(units)
+			} else {
+				rec.Units, err = bp.appendRandomLayer(units, weightInit)
+			}
+		}
+		if err != nil {
+			bp.Config.Layers.Hidden, bp.Config.Layers.Output = hidden, output
+		}
 
 	case "AppendCNNAndDenseLayer":
-		filterSize := rand.Intn(3) + 3
-		numFilters := rand.Intn(neuronRange[1]-neuronRange[0]+1) + neuronRange[0]
-		stride := 1
-		padding := (filterSize - 1) / 2
-		if err := bp.AppendCNNLayer(filterSize, numFilters, stride, padding); err != nil {
-			fmt.Printf("Failed to append CNN layer: %v\n", err)
+		rec.LayerIndex, rec.LayerType = len(bp.Config.Layers.Hidden), "conv"
+		rec.FilterSize, rec.Units = inRange(cfg.FilterSizeRange), inRange(cfg.FilterCountRange)
+		rec.DenseUnits = inRange(cfg.NeuronRange)
+		if err = bp.AppendCNNLayer(rec.FilterSize, rec.Units, 1, (rec.FilterSize-1)/2, weightInit); err == nil {
+			if err = bp.AppendNewLayerFullConnections(rec.DenseUnits, weightInit); err != nil {
+				bp.Config.Layers.Hidden = bp.Config.Layers.Hidden[:rec.LayerIndex]
+			} else {
+				rec.LayersAdded = 2
+			}
 		}
-		numNewNeuronsOrFilters := rand.Intn(neuronRange[1]-neuronRange[0]+1) + neuronRange[0]
-		bp.AppendNewLayerFullConnections(numNewNeuronsOrFilters)
 
 	case "AppendLSTMLayer":
-		bp.AppendLSTMLayer()
-		numNewNeuronsOrFilters := rand.Intn(neuronRange[1]-neuronRange[0]+1) + neuronRange[0]
-		bp.AppendNewLayerFullConnections(numNewNeuronsOrFilters)
+		rec.LayerIndex, rec.LayerType = len(bp.Config.Layers.Hidden), "lstm"
+		rec.LayersAdded, rec.Units, rec.DenseUnits = 2, inRange(cfg.LSTMCellRange), inRange(cfg.NeuronRange)
+		bp.AppendLSTMLayerWithCells(rec.Units, weightInit)
+		if err = bp.AppendNewLayerFullConnections(rec.DenseUnits, weightInit); err != nil {
+			bp.Config.Layers.Hidden = bp.Config.Layers.Hidden[:rec.LayerIndex]
+			rec.LayersAdded = 0
+		}
+
+	case "RemoveLayer":
+		err = bp.removeRandomLayer(rng, &rec)
+
+	case "RemoveNeuron":
+		err = bp.removeRandomNeuron(rng, &rec)
+
+	case "PruneConnection":
+		err = bp.pruneRandomConnection(rng, &rec)
+
+	case "InsertNeuron":
+		err = bp.insertRandomNeuron(rng, &rec, weightInit)
+
+	case "WidenLayer":
+		err = bp.widenRandomLayer(inRange(cfg.NeuronRange), &rec)
 
-	default:
+	case "MutateNeuronActivation":
+		err = bp.mutateNeuronActivation(cfg.Activations, &rec)
+		structural = false
+
+	case "MutateLayerActivation":
+		err = bp.mutateLayerActivation(cfg.Activations, &rec)
+		structural = false
+
+	case "PerturbWeights":
+		rec.ParametersChanged = bp.PerturbWeights(cfg.WeightMutationRate, cfg.WeightMutationStdDev)
+		structural = false
+
+	case "ResetWeights":
+		rec.ParametersChanged = bp.ResetWeights(cfg.WeightResetRate, weightInit)
+		structural = false
+
+	case "MutateFilters":
+		rec.ParametersChanged = bp.MutateFilters(cfg.WeightMutationRate, cfg.WeightMutationStdDev)
+		structural = false
+
+	case "MutateLSTMCells":
+		rec.ParametersChanged = bp.MutateLSTMCells(cfg.WeightMutationRate, cfg.WeightMutationStdDev)
+		structural = false
+	}
+
+	// Weight mutations and failed mutations leave the structure, and so the output layer's inputs, untouched
+	if structural && err == nil {
+		// Connect the output layer to new upstream outputs without disturbing its learned weights
+		err = bp.reconnectOutputLayer(previousKeys)
+
+		// Keep the neuron and layer counts in the metadata in sync with the new structure
+		bp.UpdateMetadataCounts()
+	}
+
+	rec.NeuronsAfter = bp.countNeurons()
+	rec.LayersAfter = bp.countLayers()
+	if err != nil {
+		return rec, fmt.Errorf("%s: %w", rec.Type, err)
+	}
+	return rec, nil
+}
+
+// ApplySingleMutation applies one mutation picked uniformly from mutationTypes, using neuronRange for the
+// size of new dense layers and the filter count of new conv layers, and layerRange for
+// AppendMultipleLayers. Both ranges are normalized: inverted bounds are swapped and bounds below 1 are
+// raised to 1. Other parameters come from DefaultMutationConfig. Unknown types and failures are printed;
+// use ApplyMutation to handle them.
+func (bp *Blueprint) ApplySingleMutation(mutationTypes []string, neuronRange [2]int, layerRange [2]int) {
+	if len(mutationTypes) == 0 {
+		fmt.Println("No mutation types to pick from")
+		return
+	}
+	mutationType := mutationTypes[bp.Rand().Intn(len(mutationTypes))]
+	if !slices.Contains(MutationTypes, mutationType) {
 		fmt.Println("Unknown mutation type:", mutationType)
+		return
 	}
 
-	// Reattach the output layer with previous activation types
-	previousOutputActivationTypes := bp.GetPreviousOutputActivationTypes()
-	bp.ReattachOutputLayerZeroBias(len(previousOutputActivationTypes), previousOutputActivationTypes)
+	cfg := DefaultMutationConfig()
+	cfg.Probabilities = map[string]float64{mutationType: 1}
+	cfg.NeuronRange = normalizeRange(neuronRange)
+	cfg.LayerRange = normalizeRange(layerRange)
+	cfg.FilterCountRange = cfg.NeuronRange
+	cfg.LSTMCellRange = [2]int{1, 1}
+
+	if _, err := bp.ApplyMutation(cfg); err != nil {
+		fmt.Println("Mutation failed:", err)
+	}
+}
+
+// normalizeRange orders the bounds of an inclusive range and raises them to at least 1, so it always
+// passes MutationConfig validation.
+func normalizeRange(r [2]int) [2]int {
+	low, high := min(r[0], r[1]), max(r[0], r[1])
+	return [2]int{max(low, 1), max(high, 1)}
 }
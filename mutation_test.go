@@ -0,0 +1,132 @@
+package blueprint
+
+import "testing"
+
+func TestApplySingleMutationLegacyRanges(t *testing.T) {
+	bp := newTestNetwork(1)
+	bp.ApplySingleMutation([]string{"AppendNewLayer"}, [2]int{0, 2}, [2]int{0, 1})
+	if got := len(bp.Config.Layers.Hidden); got != 2 {
+		t.Fatalf("%d hidden layers after appending with neuron range [0, 2], want 2", got)
+	}
+	if n := len(bp.Config.Layers.Hidden[1].Neurons); n < 1 || n > 2 {
+		t.Fatalf("appended layer has %d neurons, want 1 or 2", n)
+	}
+
+	// Repeated appends add exactly the requested number of neurons each time
+	for i := 0; i < 3; i++ {
+		bp.ApplySingleMutation([]string{"AppendNewLayer"}, [2]int{2, 2}, [2]int{1, 1})
+		last := bp.Config.Layers.Hidden[len(bp.Config.Layers.Hidden)-1]
+		if got := len(last.Neurons); got != 2 {
+			t.Fatalf("append %d: new layer has %d neurons, want 2", i, got)
+		}
+	}
+}
+
+func TestApplySingleMutationNormalizesRanges(t *testing.T) {
+	// An inverted range is swapped rather than rejected
+	bp := newTestNetwork(1)
+	bp.ApplySingleMutation([]string{"AppendMultipleLayers"}, [2]int{2, 1}, [2]int{3, 0})
+	if got := len(bp.Config.Layers.Hidden); got < 2 || got > 4 {
+		t.Fatalf("%d hidden layers after appending with layer range [3, 0], want 2 to 4", got)
+	}
+
+	// The neuron range also sets the filter count of new conv layers
+	bp = newTestNetwork(2)
+	bp.Config.Layers.Input = Layer{LayerType: "conv", Shape: []int{6, 6}}
+	bp.Config.Layers.Hidden = nil
+	bp.ApplySingleMutation([]string{"AppendCNNAndDenseLayer"}, [2]int{10, 10}, [2]int{1, 1})
+	if len(bp.Config.Layers.Hidden) == 0 {
+		t.Fatal("no conv layer was appended")
+	}
+	if got := len(bp.Config.Layers.Hidden[0].Filters); got != 10 {
+		t.Fatalf("appended conv layer has %d filters, want 10", got)
+	}
+}
+
+func TestApplySingleMutationSkipsUnknownTypes(t *testing.T) {
+	appended := 0
+	for seed := int64(0); seed < 20; seed++ {
+		bp := newTestNetwork(seed)
+		bp.ApplySingleMutation([]string{"Bogus", "AppendNewLayer"}, [2]int{1, 2}, [2]int{1, 1})
+		appended += len(bp.Config.Layers.Hidden) - 1
+	}
+	// Each call picks either type with equal chance, so an unknown type must not abort the known one
+	if appended == 0 || appended == 20 {
+		t.Fatalf("AppendNewLayer applied in %d of 20 calls, want some but not all", appended)
+	}
+}
+
+func TestMutationRecordDescribesAppendedDenseLayer(t *testing.T) {
+	for _, mutationType := range []string{"AppendLSTMLayer", "AppendCNNAndDenseLayer"} {
+		bp := newTestNetwork(2)
+		if mutationType == "AppendCNNAndDenseLayer" {
+			// Conv layers can only be appended directly after a conv input
+			bp.Config.Layers.Input = Layer{LayerType: "conv", Shape: []int{6, 6}}
+			bp.Config.Layers.Hidden = nil
+		}
+		cfg := DefaultMutationConfig()
+		cfg.Probabilities = map[string]float64{mutationType: 1}
+		cfg.NeuronRange = [2]int{2, 6}
+		rec, err := bp.ApplyMutation(cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", mutationType, err)
+		}
+		dense := bp.Config.Layers.Hidden[rec.LayerIndex+1]
+		if rec.DenseUnits != len(dense.Neurons) {
+			t.Errorf("%s: record has %d dense units, appended layer has %d neurons", mutationType, rec.DenseUnits, len(dense.Neurons))
+		}
+	}
+}
+
+func TestEvolverCountsMutationFailures(t *testing.T) {
+	e, err := NewEvolver(newTestNetwork(3), 5, parameterCount, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Mutation.Probabilities = map[string]float64{"RemoveLayer": 1}
+	if err := e.Step(); err != nil {
+		t.Fatal(err)
+	}
+	// A single hidden layer cannot be removed, so every non-elite child fails to mutate
+	if want := 5 - e.EliteCount; e.MutationFailures != want {
+		t.Fatalf("counted %d mutation failures, want %d", e.MutationFailures, want)
+	}
+}
+
+func TestRandomAppendsReadNewNeuronsWithZeroWeight(t *testing.T) {
+	for _, mutationType := range []string{"AppendNewLayer", "AppendMultipleLayers"} {
+		bp := newTestNetwork(5)
+		bias := bp.Config.Layers.Output.Neurons["neuron5"].Bias
+
+		cfg := DefaultMutationConfig()
+		cfg.Probabilities = map[string]float64{mutationType: 1}
+		cfg.NeuronRange = [2]int{4, 4}
+		cfg.LayerRange = [2]int{2, 2}
+		rec, err := bp.ApplyMutation(cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", mutationType, err)
+		}
+		if findings := bp.Validate(); len(findings) != 0 {
+			t.Fatalf("%s: findings after the append: %+v", mutationType, findings)
+		}
+
+		// Every appended layer has exactly the drawn number of neurons
+		for _, layer := range bp.Config.Layers.Hidden[1:] {
+			if len(layer.Neurons) != 4 || rec.Units != 4 {
+				t.Errorf("%s: appended layer has %d neurons and the record %d units, want 4", mutationType, len(layer.Neurons), rec.Units)
+			}
+		}
+
+		// The output reads only the last layer's neurons, each with zero weight, and keeps its bias
+		last := bp.Config.Layers.Hidden[len(bp.Config.Layers.Hidden)-1]
+		output := bp.Config.Layers.Output.Neurons["neuron5"]
+		if len(output.Connections) != len(last.Neurons) || output.Bias != bias {
+			t.Errorf("%s: output has %d connections and bias %v, want %d and %v", mutationType, len(output.Connections), output.Bias, len(last.Neurons), bias)
+		}
+		for inputID, conn := range output.Connections {
+			if _, ok := last.Neurons[inputID]; !ok || conn.Weight != 0 {
+				t.Errorf("%s: output reads %s with weight %v, want a new neuron with weight 0", mutationType, inputID, conn.Weight)
+			}
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// blueprint/net2net.go
+package blueprint
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// InsertIdentityLayer inserts a dense layer before the hidden layer at position, or before the output
+// layer when position equals the number of hidden layers, without changing what the network computes.
+// The new layer has one linear neuron per value it receives, copying that value with weight 1, and the
+// layer after it, which must be dense, reads the copies with its old weights (Net2DeeperNet).
+func (bp *Blueprint) InsertIdentityLayer(position int) error {
+	if position < 0 || position > len(bp.Config.Layers.Hidden) {
+		return fmt.Errorf("insert position %d out of range [0, %d]", position, len(bp.Config.Layers.Hidden))
+	}
+	next := bp.trainableLayers()[position]
+	if next.LayerType != "dense" {
+		return fmt.Errorf("cannot insert identity layer before %q layer %d", next.LayerType, position)
+	}
+	in, err := bp.layerInputShape(position)
+	if err != nil {
+		return fmt.Errorf("cannot insert identity layer at %d: %w", position, err)
+	}
+	if in.Kind != "vector" {
+		return fmt.Errorf("cannot insert identity layer at %d: %w", position, &ShapeMismatchError{Expected: "vector", Actual: in.Kind})
+	}
+
+	identity := Layer{LayerType: "dense", Neurons: make(map[string]Neuron)}
+	copyOf := make(map[string]string, len(in.Keys))
+	firstID := bp.getHighestNeuronID() + 1
+	for i, key := range in.Keys {
+		neuronID := "neuron" + strconv.FormatInt(firstID+int64(i), 10)
+		identity.Neurons[neuronID] = Neuron{
+			ActivationType: "linear",
+			Connections:    map[string]Connection{key: {Weight: 1}},
+		}
+		copyOf[key] = neuronID
+	}
+
+	// Point the next layer at the copies before the slice grows and the pointer goes stale
+	for _, neuronID := range sortedKeys(next.Neurons) {
+		neuron := next.Neurons[neuronID]
+		rewired := make(map[string]Connection, len(neuron.Connections))
+		for inputID, conn := range neuron.Connections {
+			if copyID, ok := copyOf[inputID]; ok {
+				rewired[copyID] = Connection{Weight: conn.Weight}
+			}
+		}
+		neuron.Connections = rewired
+		next.Neurons[neuronID] = neuron
+	}
+
+	hidden := bp.Config.Layers.Hidden
+	bp.Config.Layers.Hidden = append(hidden[:position:position], append([]Layer{identity}, hidden[position:]...)...)
+	return nil
+}
+
+// WidenLayer adds numNew neurons to the dense hidden layer at layerIndex without changing what the
+// network computes. Each new neuron replicates a randomly chosen existing neuron, and the outgoing
+// weights of every replicated neuron are divided among its copies in the next layer, which must be
+// dense (Net2WiderNet). Softmax and log_softmax neurons are never replicated, since a copy would change
+// the normalization of its whole group. It returns the IDs of the new neurons.
+func (bp *Blueprint) WidenLayer(layerIndex, numNew int) ([]string, error) {
+	if numNew <= 0 {
+		return nil, fmt.Errorf("number of new neurons must be positive, got %d", numNew)
+	}
+	layer, next, err := bp.denseHiddenPair(layerIndex)
+	if err != nil {
+		return nil, err
+	}
+	var existing []string
+	for _, neuronID := range sortedKeys(layer.Neurons) {
+		if !slices.Contains(groupActivations, layer.Neurons[neuronID].ActivationType) {
+			existing = append(existing, neuronID)
+		}
+	}
+	if len(existing) == 0 {
+		return nil, fmt.Errorf("layer %d has no neurons to replicate", layerIndex)
+	}
+
+	rng := bp.Rand()
+	firstID := bp.getHighestNeuronID() + 1
+	copies := make(map[string][]string, len(existing))
+	newIDs := make([]string, numNew)
+	for i := range newIDs {
+		sourceID := existing[rng.Intn(len(existing))]
+		source := layer.Neurons[sourceID]
+		connections := make(map[string]Connection, len(source.Connections))
+		for inputID, conn := range source.Connections {
+			connections[inputID] = Connection{Weight: conn.Weight}
+		}
+
+		newIDs[i] = "neuron" + strconv.FormatInt(firstID+int64(i), 10)
+		layer.Neurons[newIDs[i]] = Neuron{
+			ActivationType: source.ActivationType,
+			Connections:    connections,
+			Bias:           source.Bias,
+		}
+		copies[sourceID] = append(copies[sourceID], newIDs[i])
+	}
+
+	for _, neuronID := range sortedKeys(next.Neurons) {
+		neuron := next.Neurons[neuronID]
+		for sourceID, copyIDs := range copies {
+			conn, ok := neuron.Connections[sourceID]
+			if !ok {
+				continue
+			}
+			conn.Weight /= float64(len(copyIDs) + 1)
+			neuron.Connections[sourceID] = conn
+			for _, copyID := range copyIDs {
+				neuron.Connections[copyID] = Connection{Weight: conn.Weight}
+			}
+		}
+		next.Neurons[neuronID] = neuron
+	}
+	return newIDs, nil
+}
+
+// appendIdentityLayer appends a function-preserving dense layer after the hidden layers and widens it
+// towards numNeurons. The layer starts as wide as the values it receives, so it can end up wider than
+// numNeurons but never narrower. It returns the width of the new layer, or leaves the network unchanged
+// and returns an error when the layer cannot be appended or widened.
+func (bp *Blueprint) appendIdentityLayer(numNeurons int) (int, error) {
+	position := len(bp.Config.Layers.Hidden)
+	hidden, output := bp.Config.Layers.Hidden, copyLayer(bp.Config.Layers.Output)
+	if err := bp.InsertIdentityLayer(position); err != nil {
+		return 0, err
+	}
+	width := len(bp.Config.Layers.Hidden[position].Neurons)
+	if numNeurons > width {
+		if _, err := bp.WidenLayer(position, numNeurons-width); err != nil {
+			bp.Config.Layers.Hidden, bp.Config.Layers.Output = hidden, output
+			return 0, err
+		}
+		width = numNeurons
+	}
+	return width, nil
+}
+
+// appendRandomLayer appends a dense layer of numNeurons randomly initialized neurons, fully connected to
+// the values the hidden layers produce. A dense output layer then reads only the new neurons, each with
+// zero weight, so training starts from the output biases instead of from weights learned for the old
+// inputs. It returns the width of the new layer.
+func (bp *Blueprint) appendRandomLayer(numNeurons int, initializer ...Initializer) (int, error) {
+	if err := bp.AppendNewLayerFullConnections(numNeurons, initializer...); err != nil {
+		return 0, err
+	}
+	if output := &bp.Config.Layers.Output; output.LayerType == "dense" {
+		newKeys := sortedKeys(bp.Config.Layers.Hidden[len(bp.Config.Layers.Hidden)-1].Neurons)
+		for _, outputID := range sortedKeys(output.Neurons) {
+			neuron := output.Neurons[outputID]
+			neuron.Connections = make(map[string]Connection, len(newKeys))
+			for _, key := range newKeys {
+				neuron.Connections[key] = Connection{Weight: 0}
+			}
+			output.Neurons[outputID] = neuron
+		}
+	}
+	return numNeurons, nil
+}
+
+// widenRandomLayer widens a random dense hidden layer followed by a dense layer by numNew neurons.
+func (bp *Blueprint) widenRandomLayer(numNew int, rec *MutationRecord) error {
+	var candidates []int
+	for i := range bp.Config.Layers.Hidden {
+		if layer, _, err := bp.denseHiddenPair(i); err == nil && len(layer.Neurons) > 0 {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("no dense hidden layer to widen")
+	}
+	rec.LayerIndex, rec.LayerType, rec.Units = candidates[bp.Rand().Intn(len(candidates))], "dense", numNew
+	_, err := bp.WidenLayer(rec.LayerIndex, numNew)
+	return err
+}
@@ -0,0 +1,106 @@
+package blueprint
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+// newNet2NetTestNetwork returns a 2-3-2 network whose hidden neurons use different activations.
+func newNet2NetTestNetwork() (*Blueprint, map[string]interface{}) {
+	bp := NewBlueprintWithSeed(&NetworkConfig{}, 6)
+	bp.CreateCustomNetworkConfig(2, 3, 2, []string{"sigmoid", "tanh"}, "test", "test", XavierInitializer{})
+	setActivations(&bp.Config.Layers.Hidden[0], "tanh", "sigmoid", "leaky_relu")
+	return bp, map[string]interface{}{"neuron0": 0.6, "neuron1": -0.3}
+}
+
+// assertSameOutputs fails unless the network computes want for input.
+func assertSameOutputs(t *testing.T, bp *Blueprint, input map[string]interface{}, want map[string]float64) {
+	t.Helper()
+	got, err := bp.FeedforwardWithError(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for outputID, value := range want {
+		if math.Abs(got[outputID]-value) > 1e-12 {
+			t.Errorf("%s: %v, want %v", outputID, got[outputID], value)
+		}
+	}
+}
+
+func TestInsertIdentityLayerPreservesOutputs(t *testing.T) {
+	for _, position := range []int{0, 1} {
+		bp, input := newNet2NetTestNetwork()
+		want := bp.Feedforward(input)
+		if err := bp.InsertIdentityLayer(position); err != nil {
+			t.Fatal(err)
+		}
+		if len(bp.Config.Layers.Hidden) != 2 {
+			t.Fatalf("position %d: %d hidden layers, want 2", position, len(bp.Config.Layers.Hidden))
+		}
+		assertSameOutputs(t, bp, input, want)
+	}
+}
+
+func TestWidenLayerPreservesOutputs(t *testing.T) {
+	bp, input := newNet2NetTestNetwork()
+	want := bp.Feedforward(input)
+	newIDs, err := bp.WidenLayer(0, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(newIDs) != 4 || len(bp.Config.Layers.Hidden[0].Neurons) != 7 {
+		t.Fatalf("widened layer has %d neurons, want 7", len(bp.Config.Layers.Hidden[0].Neurons))
+	}
+	assertSameOutputs(t, bp, input, want)
+}
+
+func TestWidenLayerSkipsGroupActivations(t *testing.T) {
+	bp, input := newNet2NetTestNetwork()
+	setActivations(&bp.Config.Layers.Hidden[0], "softmax", "softmax", "tanh")
+	want := bp.Feedforward(input)
+	newIDs, err := bp.WidenLayer(0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, neuronID := range newIDs {
+		if activation := bp.Config.Layers.Hidden[0].Neurons[neuronID].ActivationType; slices.Contains(groupActivations, activation) {
+			t.Errorf("replicated a %s neuron as %s", activation, neuronID)
+		}
+	}
+	assertSameOutputs(t, bp, input, want)
+
+	if err := bp.SetLayerActivation(0, "log_softmax"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bp.WidenLayer(0, 1); err == nil {
+		t.Fatal("widened a layer of log_softmax neurons")
+	}
+}
+
+func TestAppendIdentityLayerRollsBack(t *testing.T) {
+	bp, _ := newNet2NetTestNetwork()
+	// An empty hidden layer gives the identity layer nothing to copy, so it cannot be widened
+	bp.Config.Layers.Hidden[0].Neurons = map[string]Neuron{}
+	before := bp.Serialize()
+	if _, err := bp.appendIdentityLayer(2); err == nil {
+		t.Fatal("widening an empty identity layer succeeded")
+	}
+	if bp.Serialize() != before {
+		t.Fatal("the failed append changed the network")
+	}
+}
+
+func TestIdentityMutationsPreserveOutputs(t *testing.T) {
+	for _, mutationType := range []string{"AppendNewLayer", "AppendMultipleLayers", "WidenLayer"} {
+		bp, input := newNet2NetTestNetwork()
+		want := bp.Feedforward(input)
+		cfg := DefaultMutationConfig()
+		cfg.Probabilities = map[string]float64{mutationType: 1}
+		cfg.InsertionMode = "identity"
+		if _, err := bp.ApplyMutation(cfg); err != nil {
+			t.Fatalf("%s: %v", mutationType, err)
+		}
+		assertSameOutputs(t, bp, input, want)
+	}
+}
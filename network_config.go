@@ -1,18 +1,21 @@
 package blueprint
 
-import (
-	"math/rand/v2"
-	"strconv"
-)
+import "strconv"
 
 // CreateCustomNetworkConfig creates a network configuration with incrementing neuron and layer IDs.
-func (bp *Blueprint) CreateCustomNetworkConfig(numInputs, numHiddenNeurons, numOutputs int, outputActivationTypes []string, modelID, projectName string) {
-	// Initialize metadata with counts
+// Weights and biases come from the optional initializer, or a uniform [0, 1) distribution by default.
+func (bp *Blueprint) CreateCustomNetworkConfig(numInputs, numHiddenNeurons, numOutputs int, outputActivationTypes []string, modelID, projectName string, initializer ...Initializer) {
+	weightInit := pickInitializer(UniformInitializer{Low: 0, High: 1}, initializer)
+
+	// Initialize metadata with counts, keeping the seed of the random source
+	rng := bp.Rand()
 	bp.Config.Metadata = ModelMetadata{
 		ModelID:              modelID,
 		ProjectName:          projectName,
 		LastTrainingAccuracy: 0.0,
 		LastTestAccuracy:     0.0,
+		Seed:                 bp.Config.Metadata.Seed,
+		Seeded:               bp.Config.Metadata.Seeded,
 	}
 
 	// Counters for neurons and layers using int64
@@ -36,30 +39,31 @@ func (bp *Blueprint) CreateCustomNetworkConfig(numInputs, numHiddenNeurons, numO
 		LayerType: "dense",
 		Neurons:   make(map[string]Neuron),
 	}
+	hiddenWeights := weightInit.Weights(rng, numHiddenNeurons, numInputs)
 	for i := 0; i < numHiddenNeurons; i++ {
 		neuronID := "neuron" + strconv.FormatInt(neuronCount, 10)
+		connections := make(map[string]Connection)
+		for j := 0; j < numInputs; j++ {
+			inputNeuronID := "neuron" + strconv.FormatInt(int64(j), 10)
+			connections[inputNeuronID] = Connection{Weight: hiddenWeights[i][j]}
+		}
+
 		hiddenLayer.Neurons[neuronID] = Neuron{
 			ActivationType: "relu",
-			Connections: func() map[string]Connection {
-				connections := make(map[string]Connection)
-				for j := 0; j < numInputs; j++ {
-					inputNeuronID := "neuron" + strconv.FormatInt(int64(j), 10)
-					connections[inputNeuronID] = Connection{Weight: rand.Float64()}
-				}
-				return connections
-			}(),
-			Bias: rand.Float64(),
+			Connections:    connections,
+			Bias:           weightInit.Bias(rng),
 		}
 		neuronCount++
 	}
 	bp.Config.Layers.Hidden = []Layer{hiddenLayer}
 	layerCount++
 
-	// Define the output layer with customized activation types, random weights, and incremented neuron IDs
+	// Define the output layer with customized activation types, initialized weights, and incremented neuron IDs
 	outputLayer := Layer{
 		LayerType: "dense",
 		Neurons:   make(map[string]Neuron),
 	}
+	outputWeights := weightInit.Weights(rng, numOutputs, numHiddenNeurons)
 	for i := 0; i < numOutputs; i++ {
 		neuronID := "neuron" + strconv.FormatInt(neuronCount, 10)
 		activationType := "sigmoid"
@@ -70,13 +74,13 @@ func (bp *Blueprint) CreateCustomNetworkConfig(numInputs, numHiddenNeurons, numO
 		connections := make(map[string]Connection)
 		for h := 0; h < numHiddenNeurons; h++ {
 			hiddenNeuronID := "neuron" + strconv.FormatInt(int64(numInputs+h), 10)
-			connections[hiddenNeuronID] = Connection{Weight: rand.Float64()}
+			connections[hiddenNeuronID] = Connection{Weight: outputWeights[i][h]}
 		}
 
 		outputLayer.Neurons[neuronID] = Neuron{
 			ActivationType: activationType,
 			Connections:    connections,
-			Bias:           rand.Float64(),
+			Bias:           weightInit.Bias(rng),
 		}
 		neuronCount++
 	}
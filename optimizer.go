@@ -0,0 +1,222 @@
+// blueprint/optimizer.go
+package blueprint
+
+import (
+	"fmt"
+	"math"
+)
+
+// Optimizer updates the network parameters from computed gradients, keeping any per-parameter state it needs.
+type Optimizer interface {
+	Step(bp *Blueprint, grads *Gradients)
+	State() OptimizerState
+}
+
+// OptimizerState is the serializable form of an optimizer, allowing training to resume after a reload.
+// Moment maps are keyed by layer index and neuron/connection, filter or LSTM cell parameter.
+type OptimizerState struct {
+	Type          string             `json:"type"`
+	LearningRate  float64            `json:"learningRate"`
+	Beta1         float64            `json:"beta1,omitempty"`
+	Beta2         float64            `json:"beta2,omitempty"`
+	Epsilon       float64            `json:"epsilon,omitempty"`
+	Steps         int64              `json:"steps"`
+	FirstMoments  map[string]float64 `json:"firstMoments,omitempty"`
+	SecondMoments map[string]float64 `json:"secondMoments,omitempty"`
+}
+
+// SGD implements plain stochastic gradient descent.
+type SGD struct {
+	LearningRate float64
+	steps        int64
+}
+
+// NewSGD creates a stochastic gradient descent optimizer.
+func NewSGD(learningRate float64) *SGD {
+	return &SGD{LearningRate: learningRate}
+}
+
+// Step applies one gradient descent update.
+func (o *SGD) Step(bp *Blueprint, grads *Gradients) {
+	o.steps++
+	bp.ApplyGradients(grads, o.LearningRate)
+}
+
+// State returns the serializable optimizer state.
+func (o *SGD) State() OptimizerState {
+	return OptimizerState{Type: "sgd", LearningRate: o.LearningRate, Steps: o.steps}
+}
+
+// Momentum implements gradient descent with classical momentum.
+type Momentum struct {
+	LearningRate float64
+	Momentum     float64
+	steps        int64
+	velocity     map[string]float64
+}
+
+// NewMomentum creates a momentum optimizer.
+func NewMomentum(learningRate, momentum float64) *Momentum {
+	return &Momentum{
+		LearningRate: learningRate,
+		Momentum:     momentum,
+		velocity:     make(map[string]float64),
+	}
+}
+
+// Step applies one momentum update.
+func (o *Momentum) Step(bp *Blueprint, grads *Gradients) {
+	o.steps++
+	bp.updateParameters(grads, func(key string, value, grad float64) float64 {
+		v := o.Momentum*o.velocity[key] - o.LearningRate*grad
+		o.velocity[key] = v
+		return value + v
+	})
+}
+
+// State returns the serializable optimizer state.
+func (o *Momentum) State() OptimizerState {
+	return OptimizerState{
+		Type:         "momentum",
+		LearningRate: o.LearningRate,
+		Beta1:        o.Momentum,
+		Steps:        o.steps,
+		FirstMoments: copyFloatMap(o.velocity),
+	}
+}
+
+// RMSProp scales each update by a running average of squared gradients.
+type RMSProp struct {
+	LearningRate float64
+	Decay        float64
+	Epsilon      float64
+	steps        int64
+	cache        map[string]float64
+}
+
+// NewRMSProp creates an RMSProp optimizer.
+func NewRMSProp(learningRate, decay float64) *RMSProp {
+	return &RMSProp{
+		LearningRate: learningRate,
+		Decay:        decay,
+		Epsilon:      1e-8,
+		cache:        make(map[string]float64),
+	}
+}
+
+// Step applies one RMSProp update.
+func (o *RMSProp) Step(bp *Blueprint, grads *Gradients) {
+	o.steps++
+	bp.updateParameters(grads, func(key string, value, grad float64) float64 {
+		c := o.Decay*o.cache[key] + (1-o.Decay)*grad*grad
+		o.cache[key] = c
+		return value - o.LearningRate*grad/(math.Sqrt(c)+o.Epsilon)
+	})
+}
+
+// State returns the serializable optimizer state.
+func (o *RMSProp) State() OptimizerState {
+	return OptimizerState{
+		Type:          "rmsprop",
+		LearningRate:  o.LearningRate,
+		Beta2:         o.Decay,
+		Epsilon:       o.Epsilon,
+		Steps:         o.steps,
+		SecondMoments: copyFloatMap(o.cache),
+	}
+}
+
+// Adam implements adaptive moment estimation with bias correction.
+type Adam struct {
+	LearningRate float64
+	Beta1        float64
+	Beta2        float64
+	Epsilon      float64
+	steps        int64
+	m            map[string]float64
+	v            map[string]float64
+}
+
+// NewAdam creates an Adam optimizer with the commonly used default moment decay rates.
+func NewAdam(learningRate float64) *Adam {
+	return &Adam{
+		LearningRate: learningRate,
+		Beta1:        0.9,
+		Beta2:        0.999,
+		Epsilon:      1e-8,
+		m:            make(map[string]float64),
+		v:            make(map[string]float64),
+	}
+}
+
+// Step applies one Adam update.
+func (o *Adam) Step(bp *Blueprint, grads *Gradients) {
+	o.steps++
+	correction1 := 1 - math.Pow(o.Beta1, float64(o.steps))
+	correction2 := 1 - math.Pow(o.Beta2, float64(o.steps))
+	bp.updateParameters(grads, func(key string, value, grad float64) float64 {
+		m := o.Beta1*o.m[key] + (1-o.Beta1)*grad
+		v := o.Beta2*o.v[key] + (1-o.Beta2)*grad*grad
+		o.m[key] = m
+		o.v[key] = v
+		return value - o.LearningRate*(m/correction1)/(math.Sqrt(v/correction2)+o.Epsilon)
+	})
+}
+
+// State returns the serializable optimizer state.
+func (o *Adam) State() OptimizerState {
+	return OptimizerState{
+		Type:          "adam",
+		LearningRate:  o.LearningRate,
+		Beta1:         o.Beta1,
+		Beta2:         o.Beta2,
+		Epsilon:       o.Epsilon,
+		Steps:         o.steps,
+		FirstMoments:  copyFloatMap(o.m),
+		SecondMoments: copyFloatMap(o.v),
+	}
+}
+
+// NewOptimizerFromState restores an optimizer, including its moment estimates, from a saved state.
+func NewOptimizerFromState(state OptimizerState) (Optimizer, error) {
+	switch state.Type {
+	case "sgd":
+		return &SGD{LearningRate: state.LearningRate, steps: state.Steps}, nil
+	case "momentum":
+		return &Momentum{
+			LearningRate: state.LearningRate,
+			Momentum:     state.Beta1,
+			steps:        state.Steps,
+			velocity:     copyFloatMap(state.FirstMoments),
+		}, nil
+	case "rmsprop":
+		return &RMSProp{
+			LearningRate: state.LearningRate,
+			Decay:        state.Beta2,
+			Epsilon:      state.Epsilon,
+			steps:        state.Steps,
+			cache:        copyFloatMap(state.SecondMoments),
+		}, nil
+	case "adam":
+		return &Adam{
+			LearningRate: state.LearningRate,
+			Beta1:        state.Beta1,
+			Beta2:        state.Beta2,
+			Epsilon:      state.Epsilon,
+			steps:        state.Steps,
+			m:            copyFloatMap(state.FirstMoments),
+			v:            copyFloatMap(state.SecondMoments),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown optimizer type: %s", state.Type)
+	}
+}
+
+// copyFloatMap returns a copy of m that is never nil.
+func copyFloatMap(m map[string]float64) map[string]float64 {
+	copied := make(map[string]float64, len(m))
+	for k, v := range m {
+		copied[k] = v
+	}
+	return copied
+}
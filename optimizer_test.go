@@ -0,0 +1,77 @@
+package blueprint
+
+import (
+	"math"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestOptimizerStateRoundTrip(t *testing.T) {
+	optimizers := map[string]func() Optimizer{
+		"sgd":      func() Optimizer { return NewSGD(0.1) },
+		"momentum": func() Optimizer { return NewMomentum(0.1, 0.9) },
+		"rmsprop":  func() Optimizer { return NewRMSProp(0.01, 0.9) },
+		"adam":     func() Optimizer { return NewAdam(0.01) },
+	}
+	input := map[string]interface{}{"neuron0": 0.4, "neuron1": -0.6}
+
+	for _, name := range sortedKeys(optimizers) {
+		t.Run(name, func(t *testing.T) {
+			bp := NewBlueprintWithSeed(&NetworkConfig{}, 3)
+			bp.CreateCustomNetworkConfig(2, 3, 1, []string{"sigmoid"}, "test", "test", XavierInitializer{})
+			targets := targetsFor(t, bp, input, 0.8)
+
+			original := optimizers[name]()
+			step := func(bp *Blueprint, optimizer Optimizer) {
+				grads, _, err := bp.ComputeGradients(input, targets)
+				if err != nil {
+					t.Fatal(err)
+				}
+				optimizer.Step(bp, grads)
+			}
+			for i := 0; i < 3; i++ {
+				step(bp, original)
+			}
+
+			path := filepath.Join(t.TempDir(), "optimizer.json")
+			if err := bp.SaveOptimizer(path, original); err != nil {
+				t.Fatal(err)
+			}
+			restored, err := bp.LoadOptimizer(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(restored.State(), original.State()) {
+				t.Fatalf("restored state %+v, want %+v", restored.State(), original.State())
+			}
+
+			// Resuming from the restored state must continue exactly like the original optimizer
+			resumed := bp.Clone()
+			step(bp, original)
+			step(resumed, restored)
+			want, got := parameters(bp), parameters(resumed)
+			for key, value := range want {
+				if math.Abs(got[key]-value) > 1e-12 {
+					t.Errorf("%s: resumed %v, want %v", key, got[key], value)
+				}
+			}
+		})
+	}
+}
+
+// parameters returns every dense weight and bias of bp keyed like updateParameters.
+func parameters(bp *Blueprint) map[string]float64 {
+	values := make(map[string]float64)
+	for li, layer := range bp.trainableLayers() {
+		for neuronID, neuron := range layer.Neurons {
+			prefix := strconv.Itoa(li) + "/" + neuronID + "/"
+			for inputID, conn := range neuron.Connections {
+				values[prefix+inputID] = conn.Weight
+			}
+			values[prefix+"bias"] = neuron.Bias
+		}
+	}
+	return values
+}
@@ -1,23 +1,25 @@
 // blueprint/output_management.go
 package blueprint
 
-import (
-	"fmt"
-	"math/rand"
-)
+import "fmt"
 
 // GetPreviousOutputActivationTypes retrieves activation types for neurons in the output layer
 func (bp *Blueprint) GetPreviousOutputActivationTypes() []string {
 	var activationTypes []string
-	for _, neuron := range bp.Config.Layers.Output.Neurons {
-		activationTypes = append(activationTypes, neuron.ActivationType)
+	for _, neuronID := range sortedKeys(bp.Config.Layers.Output.Neurons) {
+		activationTypes = append(activationTypes, bp.Config.Layers.Output.Neurons[neuronID].ActivationType)
 	}
 	return activationTypes
 }
 
-// ReattachOutputLayerZeroBias reattaches the output layer with specified activation types and zero bias
-func (bp *Blueprint) ReattachOutputLayerZeroBias(numOutputs int, outputActivationTypes []string) {
-	lastHiddenLayer := bp.Config.Layers.Hidden[len(bp.Config.Layers.Hidden)-1]
+// ReattachOutputLayerZeroBias reattaches the output layer with specified activation types and zero bias.
+// It discards the learned output weights; ReconnectOutputLayer keeps them. It fails, leaving the output
+// layer unchanged, when the outputs of the last hidden layer cannot be determined.
+func (bp *Blueprint) ReattachOutputLayerZeroBias(numOutputs int, outputActivationTypes []string) error {
+	previousKeys, err := bp.previousLayerKeys()
+	if err != nil {
+		return err
+	}
 
 	bp.Config.Layers.Output = Layer{
 		LayerType: "dense",
@@ -32,8 +34,8 @@ func (bp *Blueprint) ReattachOutputLayerZeroBias(numOutputs int, outputActivatio
 		}
 
 		connections := make(map[string]Connection)
-		for hiddenNeuronID := range lastHiddenLayer.Neurons {
-			connections[hiddenNeuronID] = Connection{Weight: rand.Float64() - 0.5}
+		for _, hiddenNeuronID := range previousKeys {
+			connections[hiddenNeuronID] = Connection{Weight: bp.Rand().Float64() - 0.5}
 		}
 
 		bp.Config.Layers.Output.Neurons[neuronID] = Neuron{
@@ -42,4 +44,58 @@ func (bp *Blueprint) ReattachOutputLayerZeroBias(numOutputs int, outputActivatio
 			Bias:           0,
 		}
 	}
+	return nil
+}
+
+// ReconnectOutputLayer wires the output layer to the current outputs of the hidden layers while keeping
+// the output neurons' IDs, activation types, biases and every weight whose input still exists. Inputs a
+// neuron is not yet connected to are added with zero weight, so the network computes the same function;
+// a neuron that lost all of its inputs is connected to the new ones with random weights instead. It fails
+// like ReattachOutputLayerZeroBias.
+func (bp *Blueprint) ReconnectOutputLayer() error {
+	return bp.reconnectOutputLayer(nil)
+}
+
+// reconnectOutputLayer implements ReconnectOutputLayer. When previousKeys lists the hidden outputs from
+// before a mutation, only inputs that did not exist then are added, so pruned connections stay pruned.
+func (bp *Blueprint) reconnectOutputLayer(previousKeys []string) error {
+	keys, err := bp.previousLayerKeys()
+	if err != nil {
+		return err
+	}
+	current := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		current[key] = true
+	}
+	existed := make(map[string]bool, len(previousKeys))
+	for _, key := range previousKeys {
+		existed[key] = true
+	}
+
+	output := &bp.Config.Layers.Output
+	for _, neuronID := range sortedKeys(output.Neurons) {
+		neuron := output.Neurons[neuronID]
+		if neuron.Connections == nil {
+			neuron.Connections = make(map[string]Connection)
+		}
+		for inputID := range neuron.Connections {
+			if !current[inputID] {
+				delete(neuron.Connections, inputID)
+			}
+		}
+
+		retained := len(neuron.Connections) > 0
+		for _, key := range keys {
+			if _, ok := neuron.Connections[key]; ok || existed[key] && retained {
+				continue
+			}
+			if retained {
+				neuron.Connections[key] = Connection{Weight: 0}
+			} else {
+				neuron.Connections[key] = Connection{Weight: bp.Rand().Float64() - 0.5}
+			}
+		}
+		output.Neurons[neuronID] = neuron
+	}
+	return nil
 }
@@ -0,0 +1,252 @@
+// blueprint/pareto.go
+package blueprint
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Objective is one criterion of a multi-objective search. Value is called by ParetoFronts for every
+// individual it sorts, and by an Evolver once per evaluated individual; objectives with Maximize unset
+// are minimized. A NaN value counts as the worst possible one.
+type Objective struct {
+	Name     string
+	Maximize bool
+	Value    func(ind *Individual) float64
+}
+
+// FitnessObjective maximizes the value returned by the Evolver's fitness function.
+func FitnessObjective() Objective {
+	return Objective{Name: "fitness", Maximize: true, Value: func(ind *Individual) float64 {
+		return ind.Fitness
+	}}
+}
+
+// AccuracyObjective maximizes LastTestAccuracy from the metadata, which the fitness function is expected
+// to set, for example by training with a Trainer.
+func AccuracyObjective() Objective {
+	return Objective{Name: "accuracy", Maximize: true, Value: func(ind *Individual) float64 {
+		return ind.Blueprint.Config.Metadata.LastTestAccuracy
+	}}
+}
+
+// NeuronCountObjective minimizes the number of neurons in the network.
+func NeuronCountObjective() Objective {
+	return Objective{Name: "neurons", Value: func(ind *Individual) float64 {
+		return float64(ind.Blueprint.countNeurons())
+	}}
+}
+
+// FLOPsObjective minimizes the operations of a forward pass as estimated by EstimateFLOPs. Networks whose
+// cost cannot be estimated score +Inf.
+func FLOPsObjective() Objective {
+	return Objective{Name: "flops", Value: func(ind *Individual) float64 {
+		flops, err := ind.Blueprint.EstimateFLOPs()
+		if err != nil {
+			return math.Inf(1)
+		}
+		return float64(flops)
+	}}
+}
+
+// LatencyObjective minimizes the mean wall-clock time in seconds of runs forward passes on input.
+// Networks that fail on the input score +Inf. Timings are noisy, so prefer FLOPsObjective when results
+// must be reproducible.
+func LatencyObjective(input map[string]interface{}, runs int) Objective {
+	runs = max(runs, 1)
+	return Objective{Name: "latency", Value: func(ind *Individual) float64 {
+		start := time.Now()
+		for i := 0; i < runs; i++ {
+			if _, err := ind.Blueprint.FeedforwardWithError(input); err != nil {
+				return math.Inf(1)
+			}
+		}
+		return time.Since(start).Seconds() / float64(runs)
+	}}
+}
+
+// ParetoFronts computes every individual's objective values and sorts the individuals into fronts of
+// mutually non-dominated solutions, best front first (NSGA-II fast non-dominated sorting). It sets each
+// individual's Objectives, Rank (the index of its front) and Crowding distance within its front.
+func ParetoFronts(individuals []*Individual, objectives []Objective) [][]*Individual {
+	for _, ind := range individuals {
+		ind.Objectives = objectiveValues(ind, objectives)
+	}
+	return sortFronts(individuals, objectives)
+}
+
+// objectiveValues computes the value of every objective for ind, replacing NaN with the worst value.
+func objectiveValues(ind *Individual, objectives []Objective) []float64 {
+	values := make([]float64, len(objectives))
+	for k, objective := range objectives {
+		values[k] = objective.Value(ind)
+		if math.IsNaN(values[k]) {
+			values[k] = math.Inf(1)
+			if objective.Maximize {
+				values[k] = math.Inf(-1)
+			}
+		}
+	}
+	return values
+}
+
+// sortFronts implements ParetoFronts for individuals whose Objectives are already set.
+func sortFronts(individuals []*Individual, objectives []Objective) [][]*Individual {
+	dominatedBy := make([][]int, len(individuals)) // Indices each individual dominates
+	dominators := make([]int, len(individuals))    // Number of individuals dominating each one
+	for i := range individuals {
+		for j := i + 1; j < len(individuals); j++ {
+			switch {
+			case dominates(individuals[i], individuals[j], objectives):
+				dominatedBy[i] = append(dominatedBy[i], j)
+				dominators[j]++
+			case dominates(individuals[j], individuals[i], objectives):
+				dominatedBy[j] = append(dominatedBy[j], i)
+				dominators[i]++
+			}
+		}
+	}
+
+	var fronts [][]*Individual
+	var current []int
+	for i, count := range dominators {
+		if count == 0 {
+			current = append(current, i)
+		}
+	}
+	for len(current) > 0 {
+		front := make([]*Individual, len(current))
+		var next []int
+		for n, i := range current {
+			individuals[i].Rank = len(fronts)
+			front[n] = individuals[i]
+			for _, j := range dominatedBy[i] {
+				dominators[j]--
+				if dominators[j] == 0 {
+					next = append(next, j)
+				}
+			}
+		}
+		assignCrowding(front, len(objectives))
+		fronts = append(fronts, front)
+		current = next
+	}
+	return fronts
+}
+
+// dominates reports whether a is at least as good as b in every objective and better in at least one.
+func dominates(a, b *Individual, objectives []Objective) bool {
+	better := false
+	for k, objective := range objectives {
+		x, y := a.Objectives[k], b.Objectives[k]
+		if !objective.Maximize {
+			x, y = -x, -y
+		}
+		if x < y {
+			return false
+		}
+		if x > y {
+			better = true
+		}
+	}
+	return better
+}
+
+// assignCrowding sets the crowding distance of every individual in a front: the sum over objectives of
+// the normalized gap between its neighbours. Boundary individuals get +Inf so they are always kept.
+func assignCrowding(front []*Individual, numObjectives int) {
+	for _, ind := range front {
+		ind.Crowding = 0
+	}
+	sorted := append([]*Individual(nil), front...)
+	for k := 0; k < numObjectives; k++ {
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].Objectives[k] < sorted[j].Objectives[k]
+		})
+		low, high := sorted[0].Objectives[k], sorted[len(sorted)-1].Objectives[k]
+		sorted[0].Crowding = math.Inf(1)
+		sorted[len(sorted)-1].Crowding = math.Inf(1)
+		if high-low == 0 || math.IsInf(high-low, 0) || math.IsNaN(high-low) {
+			continue
+		}
+		for i := 1; i < len(sorted)-1; i++ {
+			sorted[i].Crowding += (sorted[i+1].Objectives[k] - sorted[i-1].Objectives[k]) / (high - low)
+		}
+	}
+}
+
+// crowdedLess reports whether a is preferred to b: a lower rank wins, and within a front the individual
+// in the less crowded region does.
+func crowdedLess(a, b *Individual) bool {
+	if a.Rank != b.Rank {
+		return a.Rank < b.Rank
+	}
+	return a.Crowding > b.Crowding
+}
+
+// ParetoFront evaluates the population and returns its non-dominated individuals under the Evolver's
+// objectives, or nil when no objectives are set.
+func (e *Evolver) ParetoFront() []*Individual {
+	if len(e.Objectives) == 0 {
+		return nil
+	}
+	e.Evaluate()
+	fronts := sortFronts(e.Population, e.Objectives)
+	if len(fronts) == 0 {
+		return nil
+	}
+	return fronts[0]
+}
+
+// stepNSGA2 advances one generation with NSGA-II: children are bred by crowded binary tournaments, and
+// the next population is chosen from parents and children together by selectSurvivors.
+func (e *Evolver) stepNSGA2() error {
+	e.Evaluate()
+	sortFronts(e.Population, e.Objectives)
+
+	size := len(e.Population)
+	combined := append([]*Individual(nil), e.Population...)
+	for len(combined) < 2*size {
+		child, err := e.breed()
+		if err != nil {
+			return err
+		}
+		combined = append(combined, &Individual{Blueprint: child})
+	}
+
+	e.evaluate(combined[size:])
+	e.Population = selectSurvivors(sortFronts(combined, e.Objectives), size)
+	e.Generation++
+	return nil
+}
+
+// selectSurvivors fills a population of size individuals front by front, taking the least crowded
+// individuals of the last front that only partly fits.
+func selectSurvivors(fronts [][]*Individual, size int) []*Individual {
+	next := make([]*Individual, 0, size)
+	for _, front := range fronts {
+		if len(next)+len(front) > size {
+			front = append([]*Individual(nil), front...)
+			sort.SliceStable(front, func(i, j int) bool {
+				return front[i].Crowding > front[j].Crowding
+			})
+			front = front[:size-len(next)]
+		}
+		next = append(next, front...)
+		if len(next) == size {
+			break
+		}
+	}
+	return next
+}
+
+// crowdedTournamentSelect returns the preferred of two randomly drawn individuals by crowdedLess.
+func (e *Evolver) crowdedTournamentSelect() *Individual {
+	a := e.Population[e.rng.Intn(len(e.Population))]
+	b := e.Population[e.rng.Intn(len(e.Population))]
+	if crowdedLess(b, a) {
+		return b
+	}
+	return a
+}
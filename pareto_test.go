@@ -0,0 +1,126 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+// pointObjectives minimizes two values carried in Fitness and AdjustedFitness.
+var pointObjectives = []Objective{
+	{Name: "x", Value: func(ind *Individual) float64 { return ind.Fitness }},
+	{Name: "y", Value: func(ind *Individual) float64 { return ind.AdjustedFitness }},
+}
+
+// points returns one individual per (x, y) pair.
+func points(values ...[2]float64) []*Individual {
+	individuals := make([]*Individual, len(values))
+	for i, v := range values {
+		individuals[i] = &Individual{Fitness: v[0], AdjustedFitness: v[1]}
+	}
+	return individuals
+}
+
+func TestDominates(t *testing.T) {
+	maximizeY := []Objective{pointObjectives[0], {Name: "y", Maximize: true, Value: pointObjectives[1].Value}}
+	tests := []struct {
+		a, b       [2]float64
+		objectives []Objective
+		want       bool
+	}{
+		{[2]float64{1, 1}, [2]float64{2, 2}, pointObjectives, true},
+		{[2]float64{1, 2}, [2]float64{1, 3}, pointObjectives, true},
+		{[2]float64{1, 1}, [2]float64{1, 1}, pointObjectives, false},
+		{[2]float64{1, 3}, [2]float64{2, 2}, pointObjectives, false},
+		{[2]float64{1, 3}, [2]float64{2, 2}, maximizeY, true},
+	}
+	for _, tt := range tests {
+		individuals := points(tt.a, tt.b)
+		ParetoFronts(individuals, tt.objectives)
+		if got := dominates(individuals[0], individuals[1], tt.objectives); got != tt.want {
+			t.Errorf("%v dominates %v = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParetoFrontsRanksAndCrowding(t *testing.T) {
+	individuals := points([2]float64{1, 4}, [2]float64{2, 3}, [2]float64{3, 2}, [2]float64{4, 1}, [2]float64{3, 4}, [2]float64{math.NaN(), 4.5})
+	fronts := ParetoFronts(individuals, pointObjectives)
+	if len(fronts) != 3 || len(fronts[0]) != 4 || len(fronts[1]) != 1 || len(fronts[2]) != 1 {
+		t.Fatalf("got fronts of sizes %v, want [4 1 1]", frontSizes(fronts))
+	}
+	// A NaN objective counts as the worst value, so that individual is dominated by (3, 4)
+	if individuals[5].Rank != 2 || !math.IsInf(individuals[5].Objectives[0], 1) {
+		t.Fatalf("NaN individual has rank %d and objectives %v", individuals[5].Rank, individuals[5].Objectives)
+	}
+	if individuals[4].Rank != 1 {
+		t.Fatalf("dominated individual has rank %d, want 1", individuals[4].Rank)
+	}
+
+	// The boundaries of the first front are always kept; the interior points are 2/3 apart on each axis
+	for i, want := range []float64{math.Inf(1), 4.0 / 3, 4.0 / 3, math.Inf(1)} {
+		if got := individuals[i].Crowding; got != want && math.Abs(got-want) > 1e-12 {
+			t.Errorf("individual %d has crowding %v, want %v", i, got, want)
+		}
+	}
+}
+
+func frontSizes(fronts [][]*Individual) []int {
+	sizes := make([]int, len(fronts))
+	for i, front := range fronts {
+		sizes[i] = len(front)
+	}
+	return sizes
+}
+
+func TestSelectSurvivorsFillsByFrontThenCrowding(t *testing.T) {
+	individuals := points([2]float64{1, 5}, [2]float64{5, 1}, [2]float64{2, 6}, [2]float64{3, 5.5}, [2]float64{4, 5.4}, [2]float64{6, 2})
+	fronts := ParetoFronts(individuals, pointObjectives)
+	if sizes := frontSizes(fronts); len(sizes) != 2 || sizes[0] != 2 || sizes[1] != 4 {
+		t.Fatalf("got fronts of sizes %v, want [2 4]", sizes)
+	}
+
+	survivors := selectSurvivors(fronts, 4)
+	if len(survivors) != 4 {
+		t.Fatalf("got %d survivors, want 4", len(survivors))
+	}
+	// The whole first front survives, then the two boundaries of the second front
+	for _, want := range []*Individual{individuals[0], individuals[1], individuals[2], individuals[5]} {
+		found := false
+		for _, ind := range survivors {
+			found = found || ind == want
+		}
+		if !found {
+			t.Errorf("individual (%v, %v) did not survive", want.Fitness, want.AdjustedFitness)
+		}
+	}
+}
+
+func TestEvolverComputesObjectivesOnce(t *testing.T) {
+	calls := 0
+	counted := Objective{Name: "counted", Value: func(ind *Individual) float64 {
+		calls++
+		return float64(ind.Blueprint.countNeurons())
+	}}
+	e, err := NewEvolver(newTestNetwork(3), 4, parameterCount, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Objectives = []Objective{FitnessObjective(), counted}
+
+	front, err := e.RunPareto(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each individual is measured once: the initial population and the children of two generations
+	if want := 4 + 2*4; calls != want {
+		t.Fatalf("objective measured %d times, want %d", calls, want)
+	}
+	if len(front) == 0 {
+		t.Fatal("RunPareto returned an empty front")
+	}
+	for _, ind := range front {
+		if ind.Rank != 0 {
+			t.Errorf("front member has rank %d", ind.Rank)
+		}
+	}
+}
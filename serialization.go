@@ -25,3 +25,78 @@ func (bp *Blueprint) Deserialize(data string) {
 	}
 	bp.Config = &config
 }
+
+// Clone returns a new Blueprint holding a deep copy of the NetworkConfig, including weights such as NaN
+// and Inf that JSON cannot represent. The copy has no random source of its own; call SetSeed on it to
+// make its mutations reproducible.
+func (bp *Blueprint) Clone() *Blueprint {
+	var config *NetworkConfig
+	if bp.Config != nil {
+		config = &NetworkConfig{Metadata: bp.Config.Metadata}
+		config.Metadata.ParentModelIDs = copySlice(bp.Config.Metadata.ParentModelIDs)
+		config.Metadata.ChildModelIDs = copySlice(bp.Config.Metadata.ChildModelIDs)
+		config.Layers.Input = copyLayer(bp.Config.Layers.Input)
+		if bp.Config.Layers.Hidden != nil {
+			config.Layers.Hidden = make([]Layer, len(bp.Config.Layers.Hidden))
+			for i, layer := range bp.Config.Layers.Hidden {
+				config.Layers.Hidden[i] = copyLayer(layer)
+			}
+		}
+		config.Layers.Output = copyLayer(bp.Config.Layers.Output)
+	}
+	clone := NewBlueprint(config)
+	clone.BPTTWindow = bp.BPTTWindow
+	return clone
+}
+
+// copyLayer returns a deep copy of a layer.
+func copyLayer(layer Layer) Layer {
+	copied := layer
+	if layer.Neurons != nil {
+		copied.Neurons = make(map[string]Neuron, len(layer.Neurons))
+		for neuronID, neuron := range layer.Neurons {
+			if neuron.Connections != nil {
+				connections := make(map[string]Connection, len(neuron.Connections))
+				for inputID, conn := range neuron.Connections {
+					connections[inputID] = conn
+				}
+				neuron.Connections = connections
+			}
+			copied.Neurons[neuronID] = neuron
+		}
+	}
+	if layer.Filters != nil {
+		copied.Filters = make([]Filter, len(layer.Filters))
+		for i, filter := range layer.Filters {
+			copied.Filters[i] = Filter{Bias: filter.Bias}
+			if filter.Weights != nil {
+				copied.Filters[i].Weights = make([][]float64, len(filter.Weights))
+				for r, row := range filter.Weights {
+					copied.Filters[i].Weights[r] = copySlice(row)
+				}
+			}
+		}
+	}
+	if layer.LSTMCells != nil {
+		copied.LSTMCells = make([]LSTMCell, len(layer.LSTMCells))
+		for i, cell := range layer.LSTMCells {
+			copied.LSTMCells[i] = LSTMCell{
+				InputWeights:  copySlice(cell.InputWeights),
+				ForgetWeights: copySlice(cell.ForgetWeights),
+				OutputWeights: copySlice(cell.OutputWeights),
+				CellWeights:   copySlice(cell.CellWeights),
+				Bias:          cell.Bias,
+			}
+		}
+	}
+	copied.Shape = copySlice(layer.Shape)
+	return copied
+}
+
+// copySlice returns a copy of s that is nil when s is nil.
+func copySlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(s)), s...)
+}
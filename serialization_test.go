@@ -0,0 +1,35 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCloneCopiesNonFiniteWeights(t *testing.T) {
+	bp := newTestNetwork(1)
+	bp.Config.Metadata.ChildModelIDs = []string{"child"}
+	neuron := bp.Config.Layers.Output.Neurons["neuron5"]
+	neuron.Bias = math.NaN()
+	neuron.Connections["neuron2"] = Connection{Weight: math.Inf(1)}
+	bp.Config.Layers.Output.Neurons["neuron5"] = neuron
+
+	clone := bp.Clone()
+	cloned := clone.Config.Layers.Output.Neurons["neuron5"]
+	if !math.IsNaN(cloned.Bias) || !math.IsInf(cloned.Connections["neuron2"].Weight, 1) {
+		t.Fatalf("clone lost the non-finite values: %+v", cloned)
+	}
+
+	// Changing the clone must not reach the original
+	cloned.Connections["neuron2"] = Connection{Weight: 1}
+	clone.Config.Layers.Hidden[0].Neurons["neuron2"] = Neuron{ActivationType: "tanh"}
+	clone.Config.Metadata.ChildModelIDs[0] = "other"
+	if !math.IsInf(bp.Config.Layers.Output.Neurons["neuron5"].Connections["neuron2"].Weight, 1) {
+		t.Fatal("changing a cloned connection changed the original")
+	}
+	if bp.Config.Layers.Hidden[0].Neurons["neuron2"].ActivationType != "relu" {
+		t.Fatal("changing a cloned neuron changed the original")
+	}
+	if bp.Config.Metadata.ChildModelIDs[0] != "child" {
+		t.Fatal("changing the cloned metadata changed the original")
+	}
+}
@@ -0,0 +1,171 @@
+// blueprint/shapes.go
+package blueprint
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// LayerShape describes the data produced by a layer and consumed by the next one.
+type LayerShape struct {
+	Kind     string   `json:"kind"`               // "vector", "image" or "sequence"
+	Keys     []string `json:"keys,omitempty"`     // Vector keys in natural order
+	Channels int      `json:"channels,omitempty"` // Images only
+	Height   int      `json:"height,omitempty"`   // Images only
+	Width    int      `json:"width,omitempty"`    // Images only
+	Steps    int      `json:"steps,omitempty"`    // Sequences only; 0 when the length varies
+	Features int      `json:"features,omitempty"` // Sequences only
+}
+
+// Size returns the number of values described by the shape.
+func (s LayerShape) Size() int {
+	switch s.Kind {
+	case "image":
+		return s.Channels * s.Height * s.Width
+	case "sequence":
+		return s.Steps * s.Features
+	default:
+		return len(s.Keys)
+	}
+}
+
+// InferShapes computes the output shape of every layer starting from the input layer. The first entry is
+// the shape of the input data, followed by one entry per hidden layer and one for the output layer.
+// Conv and LSTM input layers must declare their dimensions in Layer.Shape.
+func (bp *Blueprint) InferShapes() ([]LayerShape, error) {
+	return bp.inferShapes(len(bp.Config.Layers.Hidden) + 1)
+}
+
+// inferShapes computes the input shape and the output shapes of the first numLayers trainable layers.
+func (bp *Blueprint) inferShapes(numLayers int) ([]LayerShape, error) {
+	inputShape, err := bp.inputShape()
+	if err != nil {
+		return nil, err
+	}
+
+	shapes := []LayerShape{inputShape}
+	for i, layer := range bp.trainableLayers()[:numLayers] {
+		shape, err := inferLayerShape(*layer, shapes[len(shapes)-1])
+		if err != nil {
+			return shapes, &LayerError{LayerIndex: i, LayerType: layer.LayerType, Err: err}
+		}
+		shapes = append(shapes, shape)
+	}
+	return shapes, nil
+}
+
+// inputShape returns the shape of the data loaded by the input layer.
+func (bp *Blueprint) inputShape() (LayerShape, error) {
+	inputLayer := bp.Config.Layers.Input
+	switch inputLayer.LayerType {
+	case "dense":
+		return LayerShape{Kind: "vector", Keys: sortedKeys(inputLayer.Neurons)}, nil
+	case "conv":
+		if len(inputLayer.Shape) != 2 {
+			return LayerShape{}, &LayerError{LayerIndex: -1, LayerType: "conv", Err: fmt.Errorf("input shape must be [height, width], got %v", inputLayer.Shape)}
+		}
+		return LayerShape{Kind: "image", Channels: 1, Height: inputLayer.Shape[0], Width: inputLayer.Shape[1]}, nil
+	case "lstm":
+		if len(inputLayer.Shape) != 2 {
+			return LayerShape{}, &LayerError{LayerIndex: -1, LayerType: "lstm", Err: fmt.Errorf("input shape must be [steps, features], got %v", inputLayer.Shape)}
+		}
+		return LayerShape{Kind: "sequence", Steps: inputLayer.Shape[0], Features: inputLayer.Shape[1]}, nil
+	default:
+		return LayerShape{}, &LayerError{LayerIndex: -1, LayerType: inputLayer.LayerType, Err: &UnknownLayerTypeError{LayerType: inputLayer.LayerType}}
+	}
+}
+
+// inferLayerShape computes the shape a layer produces from the shape it receives.
+func inferLayerShape(layer Layer, in LayerShape) (LayerShape, error) {
+	switch layer.LayerType {
+	case "dense":
+		if in.Kind != "vector" {
+			return LayerShape{}, &ShapeMismatchError{Expected: "vector", Actual: in.Kind}
+		}
+		return LayerShape{Kind: "vector", Keys: sortedKeys(layer.Neurons)}, nil
+
+	case "conv":
+		if in.Kind != "image" {
+			return LayerShape{}, &ShapeMismatchError{Expected: "image", Actual: in.Kind}
+		}
+		if layer.Stride <= 0 {
+			return LayerShape{}, fmt.Errorf("stride must be positive, got %d", layer.Stride)
+		}
+		count := 0
+		for f, filter := range layer.Filters {
+			if len(filter.Weights) == 0 || len(filter.Weights[0]) == 0 {
+				return LayerShape{}, fmt.Errorf("filter %d has no weights", f)
+			}
+			outHeight := (in.Height+2*layer.Padding-len(filter.Weights))/layer.Stride + 1
+			outWidth := (in.Width+2*layer.Padding-len(filter.Weights[0]))/layer.Stride + 1
+			if outHeight <= 0 || outWidth <= 0 {
+				return LayerShape{}, &ShapeMismatchError{
+					Expected: fmt.Sprintf("image of at least %dx%d", len(filter.Weights), len(filter.Weights[0])),
+					Actual:   fmt.Sprintf("%dx%d with padding %d", in.Height, in.Width, layer.Padding),
+				}
+			}
+			count += in.Channels * outHeight * outWidth
+		}
+		return LayerShape{Kind: "vector", Keys: indexedKeys("conv_output", count)}, nil
+
+	case "lstm":
+		if in.Kind != "vector" && in.Kind != "sequence" {
+			return LayerShape{}, &ShapeMismatchError{Expected: "vector or sequence", Actual: in.Kind}
+		}
+		return LayerShape{Kind: "vector", Keys: indexedKeys("lstm", len(layer.LSTMCells))}, nil
+
+	default:
+		return LayerShape{}, &UnknownLayerTypeError{LayerType: layer.LayerType}
+	}
+}
+
+// featureWidth returns the number of values per time step an LSTM layer receives from this shape.
+func (s LayerShape) featureWidth() int {
+	if s.Kind == "sequence" {
+		return s.Features
+	}
+	return len(s.Keys)
+}
+
+// lastHiddenShape returns the shape of the data that a layer appended after the hidden layers would receive.
+func (bp *Blueprint) lastHiddenShape() (LayerShape, error) {
+	shapes, err := bp.inferShapes(len(bp.Config.Layers.Hidden))
+	if err != nil {
+		return LayerShape{}, err
+	}
+	return shapes[len(shapes)-1], nil
+}
+
+// previousLayerKeys returns the keys that a dense layer appended after the hidden layers should connect to.
+// When the shapes cannot be inferred it falls back to the neurons or cells of the last layer, except for
+// conv layers, whose outputs depend on the image size declared in the input layer's Shape.
+func (bp *Blueprint) previousLayerKeys() ([]string, error) {
+	shape, err := bp.lastHiddenShape()
+	if err == nil {
+		return shape.Keys, nil
+	}
+	if len(bp.Config.Layers.Hidden) == 0 {
+		if bp.Config.Layers.Input.LayerType != "dense" {
+			return nil, fmt.Errorf("cannot connect to the %q input layer: %w", bp.Config.Layers.Input.LayerType, err)
+		}
+		return sortedKeys(bp.Config.Layers.Input.Neurons), nil
+	}
+	lastLayer := bp.Config.Layers.Hidden[len(bp.Config.Layers.Hidden)-1]
+	switch lastLayer.LayerType {
+	case "lstm":
+		return indexedKeys("lstm", len(lastLayer.LSTMCells)), nil
+	case "conv":
+		return nil, fmt.Errorf("cannot infer the outputs of conv layer %d, set Shape on the input layer: %w", len(bp.Config.Layers.Hidden)-1, err)
+	default:
+		return sortedKeys(lastLayer.Neurons), nil
+	}
+}
+
+// indexedKeys returns the keys prefix0 through prefix(count-1).
+func indexedKeys(prefix string, count int) []string {
+	keys := make([]string, count)
+	for i := range keys {
+		keys[i] = prefix + strconv.Itoa(i)
+	}
+	return keys
+}
@@ -0,0 +1,63 @@
+package blueprint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInferShapes(t *testing.T) {
+	bp, _ := newConvTestNetwork(t, 2, 1, "relu")
+	shapes, err := bp.InferShapes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A 5x6 image padded by 1 and read by 3x3 filters with stride 2 gives 3x3 maps for each of 2 filters
+	want := []string{"image", "vector", "vector", "vector"}
+	for i, shape := range shapes {
+		if shape.Kind != want[i] {
+			t.Fatalf("shape %d is %q, want %q", i, shape.Kind, want[i])
+		}
+	}
+	if shapes[0].Height != 5 || shapes[0].Width != 6 || shapes[1].Size() != 18 {
+		t.Fatalf("got input %+v and conv output size %d, want 5x6 and 18", shapes[0], shapes[1].Size())
+	}
+	if !reflect.DeepEqual(shapes[3].Keys, []string{"output0", "output1"}) {
+		t.Fatalf("output keys %v", shapes[3].Keys)
+	}
+}
+
+func TestAppendAfterConvNeedsInputShape(t *testing.T) {
+	bp := NewBlueprintWithSeed(&NetworkConfig{}, 13)
+	bp.Config.Layers.Input = Layer{LayerType: "conv"}
+	if err := bp.AppendCNNLayer(3, 2, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bp.AppendNewLayerFullConnections(4); err == nil {
+		t.Fatal("appended a dense layer after a conv layer of unknown output size")
+	}
+	if err := bp.AppendMultipleLayers(2, 4); err == nil {
+		t.Fatal("appended dense layers after a conv layer of unknown output size")
+	}
+	if err := bp.ReattachOutputLayerZeroBias(2, nil); err == nil {
+		t.Fatal("attached the output layer to a conv layer of unknown output size")
+	}
+	cfg := DefaultMutationConfig()
+	cfg.Probabilities = map[string]float64{"AppendCNNAndDenseLayer": 1}
+	if _, err := bp.ApplyMutation(cfg); err == nil {
+		t.Fatal("AppendCNNAndDenseLayer succeeded without an input shape")
+	}
+	if len(bp.Config.Layers.Hidden) != 1 {
+		t.Fatalf("failed appends left %d hidden layers, want 1", len(bp.Config.Layers.Hidden))
+	}
+
+	bp.Config.Layers.Input.Shape = []int{4, 4}
+	if err := bp.AppendNewLayerFullConnections(4); err != nil {
+		t.Fatal(err)
+	}
+	for neuronID, neuron := range bp.Config.Layers.Hidden[1].Neurons {
+		if len(neuron.Connections) != 32 {
+			t.Fatalf("neuron %s has %d connections, want one per conv output (32)", neuronID, len(neuron.Connections))
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// blueprint/speciation.go
+package blueprint
+
+import (
+	"math"
+	"sort"
+)
+
+// InnovationTracker hands out NEAT-style innovation numbers. Every distinct connection, identified by
+// the IDs of the neurons it joins, receives one number that it keeps in every network of a run.
+type InnovationTracker struct {
+	next    int64
+	numbers map[string]int64
+}
+
+// NewInnovationTracker creates a tracker whose first innovation number is 1.
+func NewInnovationTracker() *InnovationTracker {
+	return &InnovationTracker{next: 1, numbers: make(map[string]int64)}
+}
+
+// Innovation returns the innovation number of the connection from fromID to toID, assigning the next
+// free number the first time the connection is seen.
+func (t *InnovationTracker) Innovation(fromID, toID string) int64 {
+	key := fromID + "->" + toID
+	if number, ok := t.numbers[key]; ok {
+		return number
+	}
+	number := t.next
+	t.numbers[key] = number
+	t.next++
+	return number
+}
+
+// AssignInnovations stamps every dense connection of bp with its innovation number. Connections are
+// visited in natural ID order so numbering is reproducible.
+func (t *InnovationTracker) AssignInnovations(bp *Blueprint) {
+	for _, layer := range bp.trainableLayers() {
+		for _, neuronID := range sortedKeys(layer.Neurons) {
+			neuron := layer.Neurons[neuronID]
+			for _, inputID := range sortedKeys(neuron.Connections) {
+				conn := neuron.Connections[inputID]
+				conn.Innovation = t.Innovation(inputID, neuronID)
+				neuron.Connections[inputID] = conn
+			}
+		}
+	}
+}
+
+// SpeciationConfig holds the coefficients of the compatibility distance and the threshold below which
+// two networks belong to the same species.
+type SpeciationConfig struct {
+	ExcessCoefficient    float64 // Weight of genes beyond the other network's newest innovation
+	DisjointCoefficient  float64 // Weight of non-matching genes within the other network's range
+	WeightCoefficient    float64 // Weight of the mean weight difference of matching genes
+	LayerTypeCoefficient float64 // Weight of hidden layers whose types differ by index
+	Threshold            float64
+}
+
+// DefaultSpeciationConfig returns the coefficients from the original NEAT paper with a layer-type term.
+func DefaultSpeciationConfig() SpeciationConfig {
+	return SpeciationConfig{
+		ExcessCoefficient:    1.0,
+		DisjointCoefficient:  1.0,
+		WeightCoefficient:    0.4,
+		LayerTypeCoefficient: 1.0,
+		Threshold:            3.0,
+	}
+}
+
+// Species is a group of structurally similar individuals.
+type Species struct {
+	ID             int
+	Representative *Blueprint
+	Members        []*Individual
+	BestFitness    float64
+}
+
+// Speciation groups a population into species across generations and applies fitness sharing.
+type Speciation struct {
+	Config  SpeciationConfig
+	Tracker *InnovationTracker
+	Species []*Species
+
+	nextSpeciesID int
+}
+
+// NewSpeciation creates a speciation subsystem with its own innovation tracker.
+func NewSpeciation(config SpeciationConfig) *Speciation {
+	return &Speciation{
+		Config:        config,
+		Tracker:       NewInnovationTracker(),
+		nextSpeciesID: 1,
+	}
+}
+
+// CompatibilityDistance measures how different two networks are from their excess and disjoint
+// connection genes, the mean weight difference of matching genes and the number of hidden layers whose
+// types differ. Both networks should have their innovations assigned.
+func (c SpeciationConfig) CompatibilityDistance(a, b *Blueprint) float64 {
+	genesA, genesB := connectionGenes(a), connectionGenes(b)
+
+	var maxA, maxB int64
+	for innovation := range genesA {
+		maxA = max(maxA, innovation)
+	}
+	for innovation := range genesB {
+		maxB = max(maxB, innovation)
+	}
+
+	excess, disjoint, matching := 0, 0, 0
+	weightDiff := 0.0
+	count := func(genes, others map[int64]float64, otherMax int64) {
+		for innovation := range genes {
+			if _, ok := others[innovation]; ok {
+				continue
+			}
+			if innovation > otherMax {
+				excess++
+			} else {
+				disjoint++
+			}
+		}
+	}
+	count(genesA, genesB, maxB)
+	count(genesB, genesA, maxA)
+	for innovation, weight := range genesA {
+		if other, ok := genesB[innovation]; ok {
+			weightDiff += math.Abs(weight - other)
+			matching++
+		}
+	}
+	if matching > 0 {
+		weightDiff /= float64(matching)
+	}
+
+	// Small genomes are not normalized, as in NEAT
+	n := float64(max(len(genesA), len(genesB)))
+	if n < 20 {
+		n = 1
+	}
+
+	hiddenA, hiddenB := a.Config.Layers.Hidden, b.Config.Layers.Hidden
+	layerDiff := abs(len(hiddenA) - len(hiddenB))
+	for i := 0; i < min(len(hiddenA), len(hiddenB)); i++ {
+		if hiddenA[i].LayerType != hiddenB[i].LayerType {
+			layerDiff++
+		}
+	}
+
+	return c.ExcessCoefficient*float64(excess)/n +
+		c.DisjointCoefficient*float64(disjoint)/n +
+		c.WeightCoefficient*weightDiff +
+		c.LayerTypeCoefficient*float64(layerDiff)
+}
+
+// Speciate assigns innovation numbers to every individual, places each one in the first species whose
+// representative is within the compatibility threshold (creating new species as needed), drops empty
+// species and picks new representatives. It then sets every individual's AdjustedFitness by fitness
+// sharing: fitness is shifted so the weakest individual scores zero and divided by the species size.
+func (s *Speciation) Speciate(population []*Individual) {
+	for _, ind := range population {
+		s.Tracker.AssignInnovations(ind.Blueprint)
+	}
+
+	for _, species := range s.Species {
+		species.Members = nil
+	}
+	for _, ind := range population {
+		var home *Species
+		for _, species := range s.Species {
+			if s.Config.CompatibilityDistance(ind.Blueprint, species.Representative) < s.Config.Threshold {
+				home = species
+				break
+			}
+		}
+		if home == nil {
+			home = &Species{ID: s.nextSpeciesID, Representative: ind.Blueprint}
+			s.nextSpeciesID++
+			s.Species = append(s.Species, home)
+		}
+		home.Members = append(home.Members, ind)
+	}
+
+	minFitness := math.Inf(1)
+	for _, ind := range population {
+		minFitness = math.Min(minFitness, ind.Fitness)
+	}
+
+	remaining := s.Species[:0]
+	for _, species := range s.Species {
+		if len(species.Members) == 0 {
+			continue
+		}
+		sort.SliceStable(species.Members, func(i, j int) bool {
+			return species.Members[i].Fitness > species.Members[j].Fitness
+		})
+		species.Representative = species.Members[0].Blueprint
+		species.BestFitness = species.Members[0].Fitness
+		for _, ind := range species.Members {
+			ind.SpeciesID = species.ID
+			ind.AdjustedFitness = (ind.Fitness - minFitness) / float64(len(species.Members))
+		}
+		remaining = append(remaining, species)
+	}
+	s.Species = remaining
+}
+
+// connectionGenes maps the innovation number of every dense connection in bp to its weight.
+func connectionGenes(bp *Blueprint) map[int64]float64 {
+	genes := make(map[int64]float64)
+	for _, layer := range bp.trainableLayers() {
+		for _, neuron := range layer.Neurons {
+			for _, conn := range neuron.Connections {
+				if conn.Innovation != 0 {
+					genes[conn.Innovation] = conn.Weight
+				}
+			}
+		}
+	}
+	return genes
+}
+
+// abs returns the absolute value of an int.
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,149 @@
+package blueprint
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+// newGenomeNetwork returns a network whose single output neuron has one connection per gene, carrying
+// the gene's innovation number and weight, behind hidden layers of the given types.
+func newGenomeNetwork(genes map[int64]float64, hiddenTypes ...string) *Blueprint {
+	bp := NewBlueprint(&NetworkConfig{})
+	connections := make(map[string]Connection, len(genes))
+	for innovation, weight := range genes {
+		connections["in"+strconv.FormatInt(innovation, 10)] = Connection{Weight: weight, Innovation: innovation}
+	}
+	for _, layerType := range hiddenTypes {
+		bp.Config.Layers.Hidden = append(bp.Config.Layers.Hidden, Layer{LayerType: layerType})
+	}
+	bp.Config.Layers.Output = Layer{
+		LayerType: "dense",
+		Neurons:   map[string]Neuron{"out": {ActivationType: "sigmoid", Connections: connections}},
+	}
+	return bp
+}
+
+func TestInnovationTracker(t *testing.T) {
+	tracker := NewInnovationTracker()
+	if got := tracker.Innovation("neuron0", "neuron2"); got != 1 {
+		t.Fatalf("first innovation is %d, want 1", got)
+	}
+	if got := tracker.Innovation("neuron2", "neuron0"); got != 2 {
+		t.Fatalf("reversed connection got %d, want 2", got)
+	}
+	if got := tracker.Innovation("neuron0", "neuron2"); got != 1 {
+		t.Fatalf("repeated connection got %d, want 1", got)
+	}
+
+	// The same connection gets the same number in every genome, even when their weights differ
+	a, b := newTestNetwork(1), newTestNetwork(2)
+	b.Config.Layers.Hidden[0].Neurons["neuron3"].Connections["neuron9"] = Connection{Weight: 1}
+	tracker.AssignInnovations(a)
+	tracker.AssignInnovations(b)
+	seen := make(map[int64]string)
+	for _, layer := range a.trainableLayers() {
+		for neuronID, neuron := range layer.Neurons {
+			for inputID, conn := range neuron.Connections {
+				if conn.Innovation == 0 {
+					t.Fatalf("%s->%s has no innovation number", inputID, neuronID)
+				}
+				if other, ok := seen[conn.Innovation]; ok {
+					t.Fatalf("%s->%s and %s share innovation %d", inputID, neuronID, other, conn.Innovation)
+				}
+				seen[conn.Innovation] = inputID + "->" + neuronID
+			}
+		}
+	}
+	for i, layer := range b.trainableLayers() {
+		for neuronID, neuron := range layer.Neurons {
+			for inputID, conn := range neuron.Connections {
+				if inputID == "neuron9" {
+					if _, ok := seen[conn.Innovation]; ok {
+						t.Errorf("new connection reused innovation %d", conn.Innovation)
+					}
+					continue
+				}
+				if want := a.trainableLayers()[i].Neurons[neuronID].Connections[inputID].Innovation; conn.Innovation != want {
+					t.Errorf("%s->%s got innovation %d, want %d", inputID, neuronID, conn.Innovation, want)
+				}
+			}
+		}
+	}
+}
+
+func TestCompatibilityDistance(t *testing.T) {
+	config := SpeciationConfig{ExcessCoefficient: 1, DisjointCoefficient: 2, WeightCoefficient: 0.4, LayerTypeCoefficient: 3}
+	a := newGenomeNetwork(map[int64]float64{1: 0.5, 2: -0.5, 3: 1, 5: 0.2}, "conv")
+	b := newGenomeNetwork(map[int64]float64{1: 0, 2: 0.5, 4: 0.3}, "dense", "dense")
+
+	// Gene 5 is excess, genes 3 and 4 are disjoint, matching genes 1 and 2 differ by 0.5 and 1 and the
+	// hidden layers differ by one layer and one type: 1*1 + 2*2 + 0.4*0.75 + 3*2
+	want := 11.3
+	if got := config.CompatibilityDistance(a, b); math.Abs(got-want) > 1e-12 {
+		t.Fatalf("distance %v, want %v", got, want)
+	}
+	if got := config.CompatibilityDistance(b, a); math.Abs(got-want) > 1e-12 {
+		t.Fatalf("reversed distance %v, want %v", got, want)
+	}
+	if got := config.CompatibilityDistance(a, a); got != 0 {
+		t.Fatalf("distance to itself %v, want 0", got)
+	}
+
+	// Genomes of 20 or more genes are normalized by the larger gene count
+	large, other := make(map[int64]float64), make(map[int64]float64)
+	for i := int64(1); i <= 20; i++ {
+		large[i] = 0
+		if i <= 15 {
+			other[i] = 0
+		}
+	}
+	if got := config.CompatibilityDistance(newGenomeNetwork(large), newGenomeNetwork(other)); math.Abs(got-0.25) > 1e-12 {
+		t.Fatalf("normalized distance %v, want 0.25", got)
+	}
+}
+
+func TestSpeciate(t *testing.T) {
+	deep := newTestNetwork(3)
+	if err := deep.AppendNewLayerFullConnections(2); err != nil {
+		t.Fatal(err)
+	}
+	deep.ReattachOutputLayerZeroBias(1, []string{"sigmoid"})
+	population := []*Individual{
+		{Blueprint: newTestNetwork(1), Fitness: 1},
+		{Blueprint: deep, Fitness: 2},
+		{Blueprint: newTestNetwork(2), Fitness: 3},
+	}
+
+	speciation := NewSpeciation(DefaultSpeciationConfig())
+	speciation.Speciate(population)
+	if len(speciation.Species) != 2 {
+		t.Fatalf("got %d species, want 2", len(speciation.Species))
+	}
+	if population[0].SpeciesID != population[2].SpeciesID || population[0].SpeciesID == population[1].SpeciesID {
+		t.Fatalf("species IDs %d, %d, %d: want the two shallow networks together", population[0].SpeciesID, population[1].SpeciesID, population[2].SpeciesID)
+	}
+	shallow := speciation.Species[0]
+	if shallow.Representative != population[2].Blueprint || shallow.BestFitness != 3 {
+		t.Errorf("representative fitness %v, want the fittest member", shallow.BestFitness)
+	}
+
+	// Fitness is shifted by the weakest individual and shared within each species
+	for i, want := range []float64{0, 1, 1} {
+		if got := population[i].AdjustedFitness; math.Abs(got-want) > 1e-12 {
+			t.Errorf("individual %d: adjusted fitness %v, want %v", i, got, want)
+		}
+	}
+
+	// Species keep their IDs across generations, and a zero threshold puts everyone on their own
+	ids := []int{population[0].SpeciesID, population[1].SpeciesID}
+	speciation.Speciate(population)
+	if population[0].SpeciesID != ids[0] || population[1].SpeciesID != ids[1] {
+		t.Errorf("species IDs changed from %v to %d and %d", ids, population[0].SpeciesID, population[1].SpeciesID)
+	}
+	isolated := NewSpeciation(SpeciationConfig{Threshold: 0})
+	isolated.Speciate(population)
+	if len(isolated.Species) != 3 {
+		t.Errorf("a zero threshold gave %d species, want 3", len(isolated.Species))
+	}
+}
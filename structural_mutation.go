@@ -0,0 +1,250 @@
+// blueprint/structural_mutation.go
+package blueprint
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+)
+
+// RemoveLayer removes the hidden layer at index and rewires the layer after it. When the removed layer
+// is dense its weights are folded into the next layer's, which is exact for linear activations and an
+// approximation otherwise; for other layer types the next layer is reconnected with fresh weights. The
+// layer after the removed one must be dense and at least one hidden layer is always kept.
+func (bp *Blueprint) RemoveLayer(index int) error {
+	hidden := bp.Config.Layers.Hidden
+	if index < 0 || index >= len(hidden) {
+		return fmt.Errorf("hidden layer index %d out of range [0, %d)", index, len(hidden))
+	}
+	if len(hidden) <= 1 {
+		return fmt.Errorf("cannot remove the only hidden layer")
+	}
+
+	next := bp.trainableLayers()[index+1]
+	if next.LayerType != "dense" {
+		return fmt.Errorf("cannot remove layer %d: the next layer is %q, not dense", index, next.LayerType)
+	}
+	in, err := bp.layerInputShape(index)
+	if err != nil {
+		return fmt.Errorf("cannot remove layer %d: %w", index, err)
+	}
+	if in.Kind != "vector" {
+		return fmt.Errorf("cannot remove layer %d: %w", index, &ShapeMismatchError{Expected: "vector", Actual: in.Kind})
+	}
+
+	// Rewire next before the slice is shifted, while the pointer still refers to it
+	if removed := hidden[index]; removed.LayerType == "dense" {
+		foldDenseLayer(next, removed)
+	}
+	bp.reconnectDenseLayer(next, in.Keys)
+	bp.Config.Layers.Hidden = append(hidden[:index], hidden[index+1:]...)
+	bp.UpdateMetadataCounts()
+	return nil
+}
+
+// foldDenseLayer rewrites the connections of next to skip the removed dense layer, multiplying the two
+// weight matrices and carrying the removed biases into next's biases.
+func foldDenseLayer(next *Layer, removed Layer) {
+	for _, neuronID := range sortedKeys(next.Neurons) {
+		neuron := next.Neurons[neuronID]
+		folded := make(map[string]Connection)
+		for _, hiddenID := range sortedKeys(neuron.Connections) {
+			hiddenNeuron, ok := removed.Neurons[hiddenID]
+			if !ok {
+				continue
+			}
+			weight := neuron.Connections[hiddenID].Weight
+			for _, inputID := range sortedKeys(hiddenNeuron.Connections) {
+				conn := folded[inputID]
+				conn.Weight += weight * hiddenNeuron.Connections[inputID].Weight
+				folded[inputID] = conn
+			}
+			neuron.Bias += weight * hiddenNeuron.Bias
+		}
+		neuron.Connections = folded
+		next.Neurons[neuronID] = neuron
+	}
+}
+
+// RemoveNeuron deletes a neuron from the dense hidden layer at layerIndex together with every connection
+// that reads from it. The layer keeps at least one neuron and the layer after it must be dense; neurons
+// of that layer left without inputs are reconnected to the remaining ones.
+func (bp *Blueprint) RemoveNeuron(layerIndex int, neuronID string) error {
+	layer, next, err := bp.denseHiddenPair(layerIndex)
+	if err != nil {
+		return err
+	}
+	if _, ok := layer.Neurons[neuronID]; !ok {
+		return fmt.Errorf("neuron %q not found in layer %d", neuronID, layerIndex)
+	}
+	if len(layer.Neurons) <= 1 {
+		return fmt.Errorf("cannot remove the only neuron of layer %d", layerIndex)
+	}
+
+	delete(layer.Neurons, neuronID)
+	for _, id := range sortedKeys(next.Neurons) {
+		delete(next.Neurons[id].Connections, neuronID)
+	}
+	bp.reconnectDenseLayer(next, sortedKeys(layer.Neurons))
+	bp.UpdateMetadataCounts()
+	return nil
+}
+
+// PruneConnection deletes the connection from inputID to neuronID in the trainable layer at layerIndex,
+// where the output layer follows the hidden layers. A neuron's last connection is never removed.
+func (bp *Blueprint) PruneConnection(layerIndex int, neuronID, inputID string) error {
+	layers := bp.trainableLayers()
+	if layerIndex < 0 || layerIndex >= len(layers) {
+		return fmt.Errorf("layer index %d out of range [0, %d)", layerIndex, len(layers))
+	}
+	neuron, ok := layers[layerIndex].Neurons[neuronID]
+	if !ok {
+		return fmt.Errorf("neuron %q not found in layer %d", neuronID, layerIndex)
+	}
+	if _, ok := neuron.Connections[inputID]; !ok {
+		return fmt.Errorf("neuron %q has no connection from %q", neuronID, inputID)
+	}
+	if len(neuron.Connections) <= 1 {
+		return fmt.Errorf("cannot remove the last connection of neuron %q", neuronID)
+	}
+
+	delete(neuron.Connections, inputID)
+	bp.UpdateMetadataCounts()
+	return nil
+}
+
+// InsertNeuron adds a neuron with a random activation type to the dense hidden layer at layerIndex and
+// returns its ID. The neuron reads from every input of the layer with weights from the optional
+// initializer (standard normal by default), and the next layer, which must be dense, reads from it with
+// zero weights so the network's outputs are unchanged.
+func (bp *Blueprint) InsertNeuron(layerIndex int, initializer ...Initializer) (string, error) {
+	layer, next, err := bp.denseHiddenPair(layerIndex)
+	if err != nil {
+		return "", err
+	}
+	in, err := bp.layerInputShape(layerIndex)
+	if err != nil {
+		return "", fmt.Errorf("cannot insert neuron into layer %d: %w", layerIndex, err)
+	}
+	weightInit := pickInitializer(NormalInitializer{StdDev: 1}, initializer)
+
+	id := bp.getHighestNeuronID() + 1
+	neuronID := "neuron" + strconv.FormatInt(id, 10)
+	inserted := bp.newDenseLayer(1, id, in.Keys, weightInit)
+	if layer.Neurons == nil {
+		layer.Neurons = make(map[string]Neuron)
+	}
+	layer.Neurons[neuronID] = inserted.Neurons[neuronID]
+
+	for _, id := range sortedKeys(next.Neurons) {
+		neuron := next.Neurons[id]
+		if neuron.Connections == nil {
+			neuron.Connections = make(map[string]Connection)
+		}
+		neuron.Connections[neuronID] = Connection{Weight: 0}
+		next.Neurons[id] = neuron
+	}
+	bp.UpdateMetadataCounts()
+	return neuronID, nil
+}
+
+// denseHiddenPair returns the dense hidden layer at layerIndex and the dense layer that follows it.
+func (bp *Blueprint) denseHiddenPair(layerIndex int) (*Layer, *Layer, error) {
+	if layerIndex < 0 || layerIndex >= len(bp.Config.Layers.Hidden) {
+		return nil, nil, fmt.Errorf("hidden layer index %d out of range [0, %d)", layerIndex, len(bp.Config.Layers.Hidden))
+	}
+	layers := bp.trainableLayers()
+	layer, next := layers[layerIndex], layers[layerIndex+1]
+	if layer.LayerType != "dense" {
+		return nil, nil, fmt.Errorf("layer %d is %q, not dense", layerIndex, layer.LayerType)
+	}
+	if next.LayerType != "dense" {
+		return nil, nil, fmt.Errorf("layer %d is followed by %q, not dense", layerIndex, next.LayerType)
+	}
+	return layer, next, nil
+}
+
+// layerInputShape returns the shape of the data received by the trainable layer at layerIndex.
+func (bp *Blueprint) layerInputShape(layerIndex int) (LayerShape, error) {
+	shapes, err := bp.inferShapes(layerIndex)
+	if err != nil {
+		return LayerShape{}, err
+	}
+	return shapes[len(shapes)-1], nil
+}
+
+// removeRandomLayer removes a random hidden layer that RemoveLayer accepts.
+func (bp *Blueprint) removeRandomLayer(rng *rand.Rand, rec *MutationRecord) error {
+	var candidates []int
+	for i := range bp.Config.Layers.Hidden {
+		if bp.trainableLayers()[i+1].LayerType == "dense" {
+			if in, err := bp.layerInputShape(i); err == nil && in.Kind == "vector" {
+				candidates = append(candidates, i)
+			}
+		}
+	}
+	if len(candidates) == 0 || len(bp.Config.Layers.Hidden) <= 1 {
+		return fmt.Errorf("no removable hidden layer")
+	}
+	rec.LayerIndex = candidates[rng.Intn(len(candidates))]
+	rec.LayerType = bp.Config.Layers.Hidden[rec.LayerIndex].LayerType
+	return bp.RemoveLayer(rec.LayerIndex)
+}
+
+// removeRandomNeuron removes a random neuron from a dense hidden layer with more than one neuron.
+func (bp *Blueprint) removeRandomNeuron(rng *rand.Rand, rec *MutationRecord) error {
+	var candidates []int
+	for i := range bp.Config.Layers.Hidden {
+		if layer, _, err := bp.denseHiddenPair(i); err == nil && len(layer.Neurons) > 1 {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("no removable neuron")
+	}
+	rec.LayerIndex = candidates[rng.Intn(len(candidates))]
+	neuronIDs := sortedKeys(bp.Config.Layers.Hidden[rec.LayerIndex].Neurons)
+	rec.NeuronID = neuronIDs[rng.Intn(len(neuronIDs))]
+	return bp.RemoveNeuron(rec.LayerIndex, rec.NeuronID)
+}
+
+// pruneRandomConnection removes a random connection from a neuron that has more than one.
+func (bp *Blueprint) pruneRandomConnection(rng *rand.Rand, rec *MutationRecord) error {
+	type target struct {
+		layerIndex int
+		neuronID   string
+	}
+	var candidates []target
+	for li, layer := range bp.trainableLayers() {
+		for _, neuronID := range sortedKeys(layer.Neurons) {
+			if len(layer.Neurons[neuronID].Connections) > 1 {
+				candidates = append(candidates, target{li, neuronID})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("no prunable connection")
+	}
+	t := candidates[rng.Intn(len(candidates))]
+	inputIDs := sortedKeys(bp.trainableLayers()[t.layerIndex].Neurons[t.neuronID].Connections)
+	rec.LayerIndex, rec.NeuronID = t.layerIndex, t.neuronID
+	rec.InputID = inputIDs[rng.Intn(len(inputIDs))]
+	return bp.PruneConnection(rec.LayerIndex, rec.NeuronID, rec.InputID)
+}
+
+// insertRandomNeuron inserts a neuron into a random dense hidden layer followed by a dense layer.
+func (bp *Blueprint) insertRandomNeuron(rng *rand.Rand, rec *MutationRecord, weightInit Initializer) error {
+	var candidates []int
+	for i := range bp.Config.Layers.Hidden {
+		if _, _, err := bp.denseHiddenPair(i); err == nil {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("no dense hidden layer to insert into")
+	}
+	rec.LayerIndex = candidates[rng.Intn(len(candidates))]
+	neuronID, err := bp.InsertNeuron(rec.LayerIndex, weightInit)
+	rec.NeuronID = neuronID
+	return err
+}
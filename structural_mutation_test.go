@@ -0,0 +1,101 @@
+package blueprint
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInsertNeuronPreservesOutputs(t *testing.T) {
+	bp := NewBlueprintWithSeed(&NetworkConfig{}, 4)
+	bp.CreateCustomNetworkConfig(2, 3, 2, []string{"sigmoid", "tanh"}, "test", "test")
+	// A neuron without a connection map must be wired up instead of panicking
+	unconnected := bp.Config.Layers.Output.Neurons["neuron6"]
+	unconnected.Connections = nil
+	bp.Config.Layers.Output.Neurons["neuron6"] = unconnected
+
+	input := map[string]interface{}{"neuron0": 0.3, "neuron1": -0.8}
+	before := bp.Feedforward(input)
+	neuronID, err := bp.InsertNeuron(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := bp.Config.Layers.Hidden[0].Neurons[neuronID]; !ok {
+		t.Fatalf("inserted neuron %s is missing from the hidden layer", neuronID)
+	}
+	for outputID, neuron := range bp.Config.Layers.Output.Neurons {
+		if conn, ok := neuron.Connections[neuronID]; !ok || conn.Weight != 0 {
+			t.Errorf("output %s reads %+v from %s, want a zero-weight connection", outputID, conn, neuronID)
+		}
+	}
+	after := bp.Feedforward(input)
+	for outputID, value := range before {
+		if math.Abs(after[outputID]-value) > 1e-12 {
+			t.Errorf("%s: %v after inserting a neuron, want %v", outputID, after[outputID], value)
+		}
+	}
+}
+
+func TestStructuralMutationsKeepNetworkValid(t *testing.T) {
+	build := func(t *testing.T) *Blueprint {
+		bp := newTestNetwork(4)
+		if err := bp.AppendNewLayerFullConnections(3); err != nil {
+			t.Fatal(err)
+		}
+		bp.ReattachOutputLayerZeroBias(1, []string{"sigmoid"})
+		bp.UpdateMetadataCounts()
+		if findings := bp.Validate(); len(findings) != 0 {
+			t.Fatalf("test network has findings %+v", findings)
+		}
+		return bp
+	}
+
+	for _, tc := range []struct {
+		name   string
+		mutate func(bp *Blueprint) ([]string, error) // Returns the keys no connection may read any more
+	}{
+		{"RemoveLayer", func(bp *Blueprint) ([]string, error) {
+			removed := sortedKeys(bp.Config.Layers.Hidden[0].Neurons)
+			return removed, bp.RemoveLayer(0)
+		}},
+		{"RemoveNeuron", func(bp *Blueprint) ([]string, error) {
+			return []string{"neuron2"}, bp.RemoveNeuron(0, "neuron2")
+		}},
+		{"PruneConnection", func(bp *Blueprint) ([]string, error) {
+			if err := bp.PruneConnection(0, "neuron2", "neuron0"); err != nil {
+				return nil, err
+			}
+			if _, ok := bp.Config.Layers.Hidden[0].Neurons["neuron2"].Connections["neuron0"]; ok {
+				t.Error("the pruned connection is still there")
+			}
+			return nil, nil
+		}},
+		{"InsertNeuron", func(bp *Blueprint) ([]string, error) {
+			_, err := bp.InsertNeuron(1)
+			return nil, err
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bp := build(t)
+			removed, err := tc.mutate(bp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if findings := bp.Validate(); len(findings) != 0 {
+				t.Fatalf("findings after the mutation: %+v", findings)
+			}
+			for _, layer := range bp.trainableLayers() {
+				for neuronID, neuron := range layer.Neurons {
+					for _, key := range removed {
+						if _, ok := neuron.Connections[key]; ok {
+							t.Errorf("%s still reads the removed %s", neuronID, key)
+						}
+					}
+				}
+			}
+			outputs := bp.Feedforward(map[string]interface{}{"neuron0": 0.3, "neuron1": -0.8})
+			if value, ok := outputs["output0"]; !ok || math.IsNaN(value) {
+				t.Fatalf("got outputs %v, want a value for output0", outputs)
+			}
+		})
+	}
+}
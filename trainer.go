@@ -0,0 +1,179 @@
+// blueprint/trainer.go
+package blueprint
+
+import (
+	"fmt"
+	"math"
+)
+
+// Trainer runs mini-batch gradient descent over a dataset and records the results in the model metadata.
+type Trainer struct {
+	Blueprint *Blueprint
+	Optimizer Optimizer
+	Loss      Loss
+
+	Epochs          int
+	BatchSize       int
+	ValidationSplit float64 // Fraction of samples, taken from the end of the dataset, held out for validation
+	Shuffle         bool    // Shuffle the training samples at the start of every epoch
+
+	// OnEpoch, if set, is called with the metrics of every completed epoch.
+	OnEpoch func(metrics EpochMetrics)
+}
+
+// EpochMetrics holds the loss and accuracy measured at the end of an epoch.
+type EpochMetrics struct {
+	Epoch              int     `json:"epoch"`
+	TrainingLoss       float64 `json:"trainingLoss"`
+	TrainingAccuracy   float64 `json:"trainingAccuracy"`
+	ValidationLoss     float64 `json:"validationLoss"`
+	ValidationAccuracy float64 `json:"validationAccuracy"`
+}
+
+// NewTrainer creates a trainer with 10 epochs, batches of 32, a 20% validation split and shuffling enabled.
+func NewTrainer(bp *Blueprint, optimizer Optimizer, loss Loss) *Trainer {
+	return &Trainer{
+		Blueprint:       bp,
+		Optimizer:       optimizer,
+		Loss:            loss,
+		Epochs:          10,
+		BatchSize:       32,
+		ValidationSplit: 0.2,
+		Shuffle:         true,
+	}
+}
+
+// Train fits the network to the samples, returning the metrics of every epoch. When it finishes it stores
+// the final training accuracy in the model metadata; with a validation split it also stores the final
+// validation accuracy and marks the model as evaluated.
+func (t *Trainer) Train(inputs []map[string]interface{}, targets []map[string]float64) ([]EpochMetrics, error) {
+	if len(inputs) != len(targets) {
+		return nil, fmt.Errorf("got %d inputs but %d targets", len(inputs), len(targets))
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("no training samples provided")
+	}
+	if t.Epochs <= 0 || t.BatchSize <= 0 {
+		return nil, fmt.Errorf("epochs and batch size must be positive")
+	}
+	if t.ValidationSplit < 0 || t.ValidationSplit >= 1 {
+		return nil, fmt.Errorf("validation split must be in [0, 1), got %v", t.ValidationSplit)
+	}
+
+	numValidation := int(math.Round(float64(len(inputs)) * t.ValidationSplit))
+	numTraining := len(inputs) - numValidation
+	if numTraining == 0 {
+		return nil, fmt.Errorf("validation split leaves no training samples")
+	}
+
+	trainingIndices := make([]int, numTraining)
+	for i := range trainingIndices {
+		trainingIndices[i] = i
+	}
+	validationIndices := make([]int, numValidation)
+	for i := range validationIndices {
+		validationIndices[i] = numTraining + i
+	}
+
+	var history []EpochMetrics
+	for epoch := 1; epoch <= t.Epochs; epoch++ {
+		if t.Shuffle {
+			t.Blueprint.Rand().Shuffle(len(trainingIndices), func(i, j int) {
+				trainingIndices[i], trainingIndices[j] = trainingIndices[j], trainingIndices[i]
+			})
+		}
+
+		for start := 0; start < numTraining; start += t.BatchSize {
+			end := min(start+t.BatchSize, numTraining)
+			if err := t.trainBatch(inputs, targets, trainingIndices[start:end]); err != nil {
+				return history, fmt.Errorf("epoch %d: %w", epoch, err)
+			}
+		}
+
+		metrics := EpochMetrics{Epoch: epoch}
+		var err error
+		if metrics.TrainingLoss, metrics.TrainingAccuracy, err = t.evaluate(inputs, targets, trainingIndices); err != nil {
+			return history, fmt.Errorf("epoch %d: %w", epoch, err)
+		}
+		if metrics.ValidationLoss, metrics.ValidationAccuracy, err = t.evaluate(inputs, targets, validationIndices); err != nil {
+			return history, fmt.Errorf("epoch %d: %w", epoch, err)
+		}
+		history = append(history, metrics)
+		if t.OnEpoch != nil {
+			t.OnEpoch(metrics)
+		}
+	}
+
+	last := history[len(history)-1]
+	metadata := &t.Blueprint.Config.Metadata
+	metadata.LastTrainingAccuracy = last.TrainingAccuracy
+	if numValidation > 0 {
+		metadata.LastTestAccuracy = last.ValidationAccuracy
+		metadata.Evaluated = true
+	}
+
+	return history, nil
+}
+
+// trainBatch averages the gradients of the given samples and applies a single optimizer step.
+func (t *Trainer) trainBatch(inputs []map[string]interface{}, targets []map[string]float64, indices []int) error {
+	batchGrads := &Gradients{}
+	for _, idx := range indices {
+		grads, _, err := t.Blueprint.ComputeGradientsWithLoss(inputs[idx], targets[idx], t.Loss)
+		if err != nil {
+			return fmt.Errorf("sample %d: %w", idx, err)
+		}
+		batchGrads.Add(grads)
+	}
+	batchGrads.Scale(1 / float64(len(indices)))
+	t.Optimizer.Step(t.Blueprint, batchGrads)
+	return nil
+}
+
+// evaluate returns the mean loss and the accuracy of the network over the given samples.
+func (t *Trainer) evaluate(inputs []map[string]interface{}, targets []map[string]float64, indices []int) (float64, float64, error) {
+	if len(indices) == 0 {
+		return 0, 0, nil
+	}
+
+	totalLoss := 0.0
+	correct := 0
+	for _, idx := range indices {
+		outputs, err := t.Blueprint.FeedforwardWithError(inputs[idx])
+		if err != nil {
+			return 0, 0, fmt.Errorf("sample %d: %w", idx, err)
+		}
+		loss, _, err := t.Loss.Compute(outputs, targets[idx])
+		if err != nil {
+			return 0, 0, fmt.Errorf("sample %d: %w", idx, err)
+		}
+		totalLoss += loss
+		if predictionCorrect(outputs, targets[idx]) {
+			correct++
+		}
+	}
+	n := float64(len(indices))
+	return totalLoss / n, float64(correct) / n, nil
+}
+
+// predictionCorrect reports whether the outputs match the targets. With several targets the output with
+// the highest value must be the target with the highest value; a single target must be within 0.5.
+func predictionCorrect(outputs, targets map[string]float64) bool {
+	if len(targets) == 1 {
+		for id, target := range targets {
+			return math.Abs(outputs[id]-target) < 0.5
+		}
+	}
+
+	predicted, expected := "", ""
+	bestOutput, bestTarget := math.Inf(-1), math.Inf(-1)
+	for _, id := range sortedKeys(targets) {
+		if outputs[id] > bestOutput {
+			bestOutput, predicted = outputs[id], id
+		}
+		if targets[id] > bestTarget {
+			bestTarget, expected = targets[id], id
+		}
+	}
+	return predicted == expected
+}
@@ -0,0 +1,58 @@
+package blueprint
+
+import "testing"
+
+// xorDataset returns the four XOR samples for a network with inputs neuron0 and neuron1.
+func xorDataset(outputID string) ([]map[string]interface{}, []map[string]float64) {
+	var inputs []map[string]interface{}
+	var targets []map[string]float64
+	for _, sample := range [][3]float64{{0, 0, 0}, {0, 1, 1}, {1, 0, 1}, {1, 1, 0}} {
+		inputs = append(inputs, map[string]interface{}{"neuron0": sample[0], "neuron1": sample[1]})
+		targets = append(targets, map[string]float64{outputID: sample[2]})
+	}
+	return inputs, targets
+}
+
+func TestTrainerRecordsMetadata(t *testing.T) {
+	bp := NewBlueprintWithSeed(&NetworkConfig{}, 7)
+	bp.CreateCustomNetworkConfig(2, 4, 1, []string{"sigmoid"}, "test", "test", XavierInitializer{})
+	inputs, targets := xorDataset("neuron6")
+	inputs, targets = append(inputs, inputs...), append(targets, targets...)
+
+	trainer := NewTrainer(bp, NewAdam(0.05), MeanSquaredError{})
+	trainer.Epochs, trainer.BatchSize, trainer.ValidationSplit = 5, 2, 0.25
+	epochs := 0
+	trainer.OnEpoch = func(EpochMetrics) { epochs++ }
+	history, err := trainer.Train(inputs, targets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 5 || epochs != 5 {
+		t.Fatalf("got %d epochs of history and %d callbacks, want 5", len(history), epochs)
+	}
+	metadata := bp.Config.Metadata
+	if !metadata.Evaluated || metadata.LastTestAccuracy != history[4].ValidationAccuracy {
+		t.Fatalf("metadata %+v does not record the validation accuracy %v", metadata, history[4].ValidationAccuracy)
+	}
+}
+
+func TestTrainerWithoutValidationKeepsTestAccuracy(t *testing.T) {
+	bp := NewBlueprintWithSeed(&NetworkConfig{}, 8)
+	bp.CreateCustomNetworkConfig(2, 4, 1, []string{"sigmoid"}, "test", "test", XavierInitializer{})
+	bp.Config.Metadata.LastTestAccuracy = 0.75
+	inputs, targets := xorDataset("neuron6")
+
+	trainer := NewTrainer(bp, NewSGD(0.1), MeanSquaredError{})
+	trainer.Epochs, trainer.ValidationSplit = 2, 0
+	history, err := trainer.Train(inputs, targets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata := bp.Config.Metadata
+	if metadata.LastTestAccuracy != 0.75 || metadata.Evaluated {
+		t.Fatalf("training without validation changed the test results: %+v", metadata)
+	}
+	if metadata.LastTrainingAccuracy != history[1].TrainingAccuracy {
+		t.Fatalf("training accuracy %v, want %v", metadata.LastTrainingAccuracy, history[1].TrainingAccuracy)
+	}
+}
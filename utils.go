@@ -2,31 +2,34 @@
 package blueprint
 
 import (
-	"math/rand"
+	"sort"
 	"strconv"
 	"strings"
 )
 
 // randomActivationType returns a random activation type for a neuron
-func randomActivationType() string {
+func (bp *Blueprint) randomActivationType() string {
 	activationTypes := []string{"relu", "sigmoid", "tanh", "leaky_relu"}
-	return activationTypes[rand.Intn(len(activationTypes))]
+	return activationTypes[bp.Rand().Intn(len(activationTypes))]
 }
 
-// Random2DSlice generates a 2D slice of random float64 values with the given dimensions
-func Random2DSlice(rows, cols int) [][]float64 {
+// Random2DSlice generates a 2D slice of random float64 values with the given dimensions, drawn from the
+// Blueprint's random source
+func (bp *Blueprint) Random2DSlice(rows, cols int) [][]float64 {
 	slice := make([][]float64, rows)
 	for i := range slice {
-		slice[i] = RandomSlice(cols)
+		slice[i] = bp.RandomSlice(cols)
 	}
 	return slice
 }
 
-// RandomSlice generates a 1D slice of random float64 values of a given length
-func RandomSlice(length int) []float64 {
+// RandomSlice generates a 1D slice of random float64 values of a given length, drawn from the
+// Blueprint's random source
+func (bp *Blueprint) RandomSlice(length int) []float64 {
+	rng := bp.Rand()
 	slice := make([]float64, length)
 	for i := range slice {
-		slice[i] = rand.Float64()
+		slice[i] = rng.Float64()
 	}
 	return slice
 }
@@ -58,3 +61,47 @@ func (bp *Blueprint) getHighestNeuronID() int64 {
 
 	return maxID
 }
+
+// sortedKeys returns the keys of m in natural order, so "neuron2" sorts before "neuron10".
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return naturalLess(keys[i], keys[j])
+	})
+	return keys
+}
+
+// naturalLess orders IDs by their non-numeric prefix and then by their numeric suffix.
+func naturalLess(a, b string) bool {
+	prefixA, numA, okA := splitNumericSuffix(a)
+	prefixB, numB, okB := splitNumericSuffix(b)
+	if prefixA != prefixB {
+		return prefixA < prefixB
+	}
+	if okA != okB {
+		return !okA
+	}
+	if okA && numA != numB {
+		return numA < numB
+	}
+	return a < b
+}
+
+// splitNumericSuffix splits an ID such as "neuron12" into "neuron" and 12.
+func splitNumericSuffix(id string) (string, int64, bool) {
+	i := len(id)
+	for i > 0 && id[i-1] >= '0' && id[i-1] <= '9' {
+		i--
+	}
+	if i == len(id) {
+		return id, 0, false
+	}
+	num, err := strconv.ParseInt(id[i:], 10, 64)
+	if err != nil {
+		return id, 0, false
+	}
+	return id[:i], num, true
+}
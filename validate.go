@@ -0,0 +1,198 @@
+// blueprint/validate.go
+package blueprint
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ValidationFinding describes one problem found in a NetworkConfig.
+type ValidationFinding struct {
+	Severity string `json:"severity"`     // "error" or "warning"
+	Code     string `json:"code"`         // Machine-readable category, e.g. "dangling_connection"
+	Layer    string `json:"layer"`        // "input", "hidden[i]", "output" or "metadata"
+	ID       string `json:"id,omitempty"` // Neuron, filter or cell concerned, if any
+	Message  string `json:"message"`
+}
+
+// ValidationFindings is the list of findings returned by Validate.
+type ValidationFindings []ValidationFinding
+
+// HasErrors reports whether any finding has error severity.
+func (f ValidationFindings) HasErrors() bool {
+	for _, finding := range f {
+		if finding.Severity == "error" {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the network configuration for structural problems: dangling connection IDs, duplicate
+// neuron IDs, unknown activation or layer types, ragged filters, LSTM weights that do not match the
+// incoming feature width, non-positive strides and metadata counts that disagree with the layers.
+// Metadata mismatches are reported as warnings; everything else is an error.
+func (bp *Blueprint) Validate() ValidationFindings {
+	var findings ValidationFindings
+	add := func(severity, code, layer, id, format string, args ...interface{}) {
+		findings = append(findings, ValidationFinding{
+			Severity: severity,
+			Code:     code,
+			Layer:    layer,
+			ID:       id,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	// Duplicate neuron IDs across every layer
+	seen := make(map[string]string)
+	checkDuplicates := func(layerName string, layer Layer) {
+		for _, neuronID := range sortedKeys(layer.Neurons) {
+			if first, ok := seen[neuronID]; ok {
+				add("error", "duplicate_neuron_id", layerName, neuronID, "neuron ID already used in %s", first)
+				continue
+			}
+			seen[neuronID] = layerName
+		}
+	}
+	checkDuplicates("input", bp.Config.Layers.Input)
+	for i, layer := range bp.Config.Layers.Hidden {
+		checkDuplicates(fmt.Sprintf("hidden[%d]", i), layer)
+	}
+	checkDuplicates("output", bp.Config.Layers.Output)
+
+	// Walk the layers, tracking the shape each one receives when it is known
+	shape, err := bp.inputShape()
+	known := err == nil
+	if err != nil {
+		add("error", "invalid_input_layer", "input", "", "%v", err)
+	}
+
+	for i, layer := range bp.trainableLayers() {
+		layerName := "output"
+		if i < len(bp.Config.Layers.Hidden) {
+			layerName = fmt.Sprintf("hidden[%d]", i)
+		}
+
+		switch layer.LayerType {
+		case "dense":
+			var validInputs map[string]bool
+			if known && shape.Kind == "vector" {
+				validInputs = make(map[string]bool, len(shape.Keys))
+				for _, key := range shape.Keys {
+					validInputs[key] = true
+				}
+			}
+			for _, neuronID := range sortedKeys(layer.Neurons) {
+				neuron := layer.Neurons[neuronID]
+				if neuron.ActivationType != "" && !slices.Contains(SupportedActivations, neuron.ActivationType) {
+					add("error", "unknown_activation", layerName, neuronID, "unknown activation type %q", neuron.ActivationType)
+				}
+				if validInputs == nil {
+					continue
+				}
+				for _, inputID := range sortedKeys(neuron.Connections) {
+					if !validInputs[inputID] {
+						add("error", "dangling_connection", layerName, neuronID, "connection from %q does not match any output of the previous layer", inputID)
+					}
+				}
+			}
+
+		case "conv":
+			if layer.Stride <= 0 {
+				add("error", "invalid_stride", layerName, "", "stride must be positive, got %d", layer.Stride)
+			}
+			if layer.Padding < 0 {
+				add("error", "invalid_padding", layerName, "", "padding must not be negative, got %d", layer.Padding)
+			}
+			if !slices.Contains(ConvActivations(), layer.convActivation()) {
+				add("error", "unknown_activation", layerName, "", "activation type %q is not supported by conv layers", layer.ActivationType)
+			}
+			for f, filter := range layer.Filters {
+				filterID := fmt.Sprintf("filter%d", f)
+				if len(filter.Weights) == 0 || len(filter.Weights[0]) == 0 {
+					add("error", "ragged_filter", layerName, filterID, "filter has no weights")
+					continue
+				}
+				for r, row := range filter.Weights {
+					if len(row) != len(filter.Weights[0]) {
+						add("error", "ragged_filter", layerName, filterID, "row %d has %d weights, expected %d", r, len(row), len(filter.Weights[0]))
+					}
+				}
+			}
+
+		case "lstm":
+			if !known || (shape.Kind != "vector" && shape.Kind != "sequence") {
+				break
+			}
+			width := shape.featureWidth()
+			for c, cell := range layer.LSTMCells {
+				cellID := fmt.Sprintf("lstm%d", c)
+				for _, weights := range []struct {
+					name   string
+					values []float64
+				}{
+					{"input", cell.InputWeights},
+					{"forget", cell.ForgetWeights},
+					{"output", cell.OutputWeights},
+					{"cell", cell.CellWeights},
+				} {
+					if len(weights.values) != width {
+						add("error", "lstm_width_mismatch", layerName, cellID, "%s weights have length %d but the incoming feature width is %d", weights.name, len(weights.values), width)
+					}
+				}
+			}
+
+		default:
+			add("error", "unknown_layer_type", layerName, "", "unknown layer type %q", layer.LayerType)
+		}
+
+		// Work out what the next layer receives, even if this layer does not fit its input
+		if known {
+			next, err := inferLayerShape(*layer, shape)
+			if err == nil {
+				shape = next
+				continue
+			}
+			add("error", "shape_mismatch", layerName, "", "%v", err)
+		}
+		switch layer.LayerType {
+		case "dense":
+			shape, known = LayerShape{Kind: "vector", Keys: sortedKeys(layer.Neurons)}, true
+		case "lstm":
+			shape, known = LayerShape{Kind: "vector", Keys: indexedKeys("lstm", len(layer.LSTMCells))}, true
+		default:
+			known = false
+		}
+	}
+
+	// Metadata counts
+	if totalNeurons := bp.countNeurons(); bp.Config.Metadata.TotalNeurons != totalNeurons {
+		add("warning", "metadata_mismatch", "metadata", "", "TotalNeurons is %d but the layers contain %d neurons", bp.Config.Metadata.TotalNeurons, totalNeurons)
+	}
+	if totalLayers := bp.countLayers(); bp.Config.Metadata.TotalLayers != totalLayers {
+		add("warning", "metadata_mismatch", "metadata", "", "TotalLayers is %d but the network has %d layers", bp.Config.Metadata.TotalLayers, totalLayers)
+	}
+
+	return findings
+}
+
+// UpdateMetadataCounts recomputes TotalNeurons and TotalLayers from the current layers.
+func (bp *Blueprint) UpdateMetadataCounts() {
+	bp.Config.Metadata.TotalNeurons = bp.countNeurons()
+	bp.Config.Metadata.TotalLayers = bp.countLayers()
+}
+
+// countNeurons returns the number of dense neurons in the input, hidden and output layers.
+func (bp *Blueprint) countNeurons() int64 {
+	total := int64(len(bp.Config.Layers.Input.Neurons) + len(bp.Config.Layers.Output.Neurons))
+	for _, layer := range bp.Config.Layers.Hidden {
+		total += int64(len(layer.Neurons))
+	}
+	return total
+}
+
+// countLayers returns the number of layers including the input and output layers.
+func (bp *Blueprint) countLayers() int64 {
+	return int64(len(bp.Config.Layers.Hidden) + 2)
+}
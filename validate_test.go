@@ -0,0 +1,87 @@
+package blueprint
+
+import "testing"
+
+func TestValidateFreshNetworks(t *testing.T) {
+	conv, _ := newConvTestNetwork(t, 1, 1, "relu")
+	lstm, _ := newLSTMTestNetwork()
+	for name, bp := range map[string]*Blueprint{"dense": newTestNetwork(1), "conv": conv, "lstm": lstm} {
+		bp.UpdateMetadataCounts()
+		if findings := bp.Validate(); len(findings) != 0 {
+			t.Errorf("%s: fresh network has findings %+v", name, findings)
+		}
+	}
+}
+
+func TestValidateFindings(t *testing.T) {
+	dense := func(t *testing.T) *Blueprint { return newTestNetwork(1) }
+	conv := func(t *testing.T) *Blueprint {
+		bp, _ := newConvTestNetwork(t, 1, 1, "relu")
+		return bp
+	}
+	lstm := func(t *testing.T) *Blueprint {
+		bp, _ := newLSTMTestNetwork()
+		return bp
+	}
+
+	tests := []struct {
+		code     string
+		build    func(t *testing.T) *Blueprint
+		corrupt  func(bp *Blueprint)
+		layer    string
+		id       string
+		severity string
+	}{
+		{"dangling_connection", dense, func(bp *Blueprint) {
+			bp.Config.Layers.Output.Neurons["neuron5"].Connections["neuron99"] = Connection{Weight: 1}
+		}, "output", "neuron5", "error"},
+		{"duplicate_neuron_id", dense, func(bp *Blueprint) {
+			bp.Config.Layers.Hidden[0].Neurons["neuron5"] = bp.Config.Layers.Hidden[0].Neurons["neuron2"]
+		}, "output", "neuron5", "error"},
+		{"unknown_activation", dense, func(bp *Blueprint) {
+			neuron := bp.Config.Layers.Hidden[0].Neurons["neuron3"]
+			neuron.ActivationType = "bogus"
+			bp.Config.Layers.Hidden[0].Neurons["neuron3"] = neuron
+		}, "hidden[0]", "neuron3", "error"},
+		{"ragged_filter", conv, func(bp *Blueprint) {
+			weights := bp.Config.Layers.Hidden[0].Filters[1].Weights
+			weights[2] = weights[2][:2]
+		}, "hidden[0]", "filter1", "error"},
+		{"lstm_width_mismatch", lstm, func(bp *Blueprint) {
+			bp.Config.Layers.Hidden[0].LSTMCells[1].ForgetWeights = []float64{0.1, 0.2}
+		}, "hidden[0]", "lstm1", "error"},
+		{"invalid_stride", conv, func(bp *Blueprint) {
+			bp.Config.Layers.Hidden[0].Stride = 0
+		}, "hidden[0]", "", "error"},
+		{"metadata_mismatch", dense, func(bp *Blueprint) {
+			bp.Config.Metadata.TotalNeurons++
+		}, "metadata", "", "warning"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			bp := tt.build(t)
+			bp.UpdateMetadataCounts()
+			tt.corrupt(bp)
+			if tt.code != "metadata_mismatch" {
+				bp.UpdateMetadataCounts()
+			}
+
+			findings := bp.Validate()
+			found := false
+			for _, finding := range findings {
+				if finding.Code == tt.code {
+					found = true
+					if finding.Layer != tt.layer || finding.ID != tt.id || finding.Severity != tt.severity {
+						t.Errorf("got %+v, want layer %q, ID %q and severity %s", finding, tt.layer, tt.id, tt.severity)
+					}
+				}
+			}
+			if !found {
+				t.Fatalf("no %s finding in %+v", tt.code, findings)
+			}
+			if findings.HasErrors() != (tt.severity == "error") {
+				t.Errorf("HasErrors is %v for %+v", findings.HasErrors(), findings)
+			}
+		})
+	}
+}
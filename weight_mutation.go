@@ -0,0 +1,117 @@
+// blueprint/weight_mutation.go
+package blueprint
+
+import "math/rand"
+
+// Default rates used by DefaultMutationConfig.
+const (
+	DefaultWeightMutationRate   = 0.1  // Probability that a parameter is perturbed
+	DefaultWeightMutationStdDev = 0.5  // Standard deviation of the Gaussian perturbation
+	DefaultWeightResetRate      = 0.05 // Probability that a parameter is re-initialized
+)
+
+// PerturbWeights adds Gaussian noise with the given standard deviation to each dense connection weight
+// and bias with probability rate. It returns the number of parameters changed.
+func (bp *Blueprint) PerturbWeights(rate, stdDev float64) int {
+	changed := 0
+	perturb := gaussianPerturbation(bp.Rand(), rate, stdDev, &changed)
+	bp.mutateDenseParameters(
+		func(value float64, _, _ int) float64 { return perturb(value) },
+		perturb,
+	)
+	return changed
+}
+
+// ResetWeights replaces each dense connection weight and bias with probability rate by a fresh value
+// from the initializer, which defaults to the one AppendNewLayerFullConnections uses. Weights are drawn
+// for the neuron's fan-in and its layer's fan-out. It returns the number of parameters changed.
+func (bp *Blueprint) ResetWeights(rate float64, initializer ...Initializer) int {
+	rng := bp.Rand()
+	changed := 0
+	weightInit := pickInitializer(NormalInitializer{StdDev: 1}, initializer)
+	bp.mutateDenseParameters(
+		func(value float64, fanIn, fanOut int) float64 {
+			if rng.Float64() < rate {
+				changed++
+				return drawWeight(rng, weightInit, fanIn, fanOut)
+			}
+			return value
+		},
+		func(value float64) float64 {
+			if rng.Float64() < rate {
+				changed++
+				return weightInit.Bias(rng)
+			}
+			return value
+		},
+	)
+	return changed
+}
+
+// MutateFilters adds Gaussian noise to each kernel weight and bias of every convolutional filter with
+// probability rate. It returns the number of parameters changed.
+func (bp *Blueprint) MutateFilters(rate, stdDev float64) int {
+	changed := 0
+	perturb := gaussianPerturbation(bp.Rand(), rate, stdDev, &changed)
+	for _, layer := range bp.trainableLayers() {
+		for fi := range layer.Filters {
+			filter := &layer.Filters[fi]
+			for i := range filter.Weights {
+				for j := range filter.Weights[i] {
+					filter.Weights[i][j] = perturb(filter.Weights[i][j])
+				}
+			}
+			filter.Bias = perturb(filter.Bias)
+		}
+	}
+	return changed
+}
+
+// MutateLSTMCells adds Gaussian noise to each gate weight and bias of every LSTM cell with probability
+// rate. It returns the number of parameters changed.
+func (bp *Blueprint) MutateLSTMCells(rate, stdDev float64) int {
+	changed := 0
+	perturb := gaussianPerturbation(bp.Rand(), rate, stdDev, &changed)
+	for _, layer := range bp.trainableLayers() {
+		for ci := range layer.LSTMCells {
+			cell := &layer.LSTMCells[ci]
+			for _, weights := range [][]float64{cell.InputWeights, cell.ForgetWeights, cell.OutputWeights, cell.CellWeights} {
+				for i := range weights {
+					weights[i] = perturb(weights[i])
+				}
+			}
+			cell.Bias = perturb(cell.Bias)
+		}
+	}
+	return changed
+}
+
+// mutateDenseParameters replaces every dense connection weight and bias by the callbacks' results,
+// visiting neurons and connections in natural ID order so mutations are reproducible for a seed. The
+// weight callback also receives the neuron's fan-in and the layer's fan-out.
+func (bp *Blueprint) mutateDenseParameters(weight func(value float64, fanIn, fanOut int) float64, bias func(value float64) float64) {
+	for _, layer := range bp.trainableLayers() {
+		for _, neuronID := range sortedKeys(layer.Neurons) {
+			neuron := layer.Neurons[neuronID]
+			for _, inputID := range sortedKeys(neuron.Connections) {
+				conn := neuron.Connections[inputID]
+				conn.Weight = weight(conn.Weight, len(neuron.Connections), len(layer.Neurons))
+				neuron.Connections[inputID] = conn
+			}
+			neuron.Bias = bias(neuron.Bias)
+			layer.Neurons[neuronID] = neuron
+		}
+	}
+}
+
+// gaussianPerturbation returns a function that adds N(0, stdDev²) noise to a value with probability rate,
+// counting the values it changes in changed.
+func gaussianPerturbation(rng *rand.Rand, rate, stdDev float64, changed *int) func(float64) float64 {
+	return func(value float64) float64 {
+		if rng.Float64() < rate {
+			*changed++
+			return value + rng.NormFloat64()*stdDev
+		}
+		return value
+	}
+}
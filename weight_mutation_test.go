@@ -0,0 +1,106 @@
+package blueprint
+
+import "testing"
+
+// denseValues returns every dense connection weight and bias of bp in natural ID order.
+func denseValues(bp *Blueprint) []float64 {
+	var values []float64
+	for _, layer := range bp.trainableLayers() {
+		for _, neuronID := range sortedKeys(layer.Neurons) {
+			neuron := layer.Neurons[neuronID]
+			for _, inputID := range sortedKeys(neuron.Connections) {
+				values = append(values, neuron.Connections[inputID].Weight)
+			}
+			values = append(values, neuron.Bias)
+		}
+	}
+	return values
+}
+
+// filterValues returns every kernel weight and bias of the convolutional filters of bp.
+func filterValues(bp *Blueprint) []float64 {
+	var values []float64
+	for _, layer := range bp.trainableLayers() {
+		for _, filter := range layer.Filters {
+			for _, row := range filter.Weights {
+				values = append(values, row...)
+			}
+			values = append(values, filter.Bias)
+		}
+	}
+	return values
+}
+
+// lstmValues returns every gate weight and bias of the LSTM cells of bp.
+func lstmValues(bp *Blueprint) []float64 {
+	var values []float64
+	for _, layer := range bp.trainableLayers() {
+		for _, cell := range layer.LSTMCells {
+			for _, weights := range [][]float64{cell.InputWeights, cell.ForgetWeights, cell.OutputWeights, cell.CellWeights} {
+				values = append(values, weights...)
+			}
+			values = append(values, cell.Bias)
+		}
+	}
+	return values
+}
+
+func TestWeightMutations(t *testing.T) {
+	dense := func(t *testing.T) *Blueprint { return newTestNetwork(1) }
+	conv := func(t *testing.T) *Blueprint {
+		bp, _ := newConvTestNetwork(t, 1, 0, "relu")
+		return bp
+	}
+	lstm := func(t *testing.T) *Blueprint {
+		bp, _ := newLSTMTestNetwork()
+		return bp
+	}
+
+	for _, tc := range []struct {
+		name   string
+		build  func(t *testing.T) *Blueprint
+		values func(bp *Blueprint) []float64
+		mutate func(bp *Blueprint, rate float64) int
+	}{
+		{"PerturbWeights", dense, denseValues, func(bp *Blueprint, rate float64) int { return bp.PerturbWeights(rate, 0.5) }},
+		{"ResetWeights", dense, denseValues, func(bp *Blueprint, rate float64) int { return bp.ResetWeights(rate) }},
+		{"MutateFilters", conv, filterValues, func(bp *Blueprint, rate float64) int { return bp.MutateFilters(rate, 0.5) }},
+		{"MutateLSTMCells", lstm, lstmValues, func(bp *Blueprint, rate float64) int { return bp.MutateLSTMCells(rate, 0.5) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			build := func() *Blueprint {
+				bp := tc.build(t)
+				bp.SetSeed(5)
+				return bp
+			}
+
+			// Rate 0 leaves the network untouched
+			bp := build()
+			before := bp.Serialize()
+			if changed := tc.mutate(bp, 0); changed != 0 || bp.Serialize() != before {
+				t.Fatalf("rate 0 changed %d parameters", changed)
+			}
+
+			// Rate 1 changes every parameter it covers
+			original := tc.values(bp)
+			if len(original) == 0 {
+				t.Fatal("the test network has no parameters to mutate")
+			}
+			changed := tc.mutate(bp, 1)
+			if changed != len(original) {
+				t.Fatalf("rate 1 changed %d of %d parameters", changed, len(original))
+			}
+			for i, value := range tc.values(bp) {
+				if value == original[i] {
+					t.Errorf("parameter %d kept its value %v", i, value)
+				}
+			}
+
+			// The same seed gives the same mutation
+			a, b := build(), build()
+			if tc.mutate(a, 0.5) != tc.mutate(b, 0.5) || a.Serialize() != b.Serialize() {
+				t.Fatal("the same seed mutated differently")
+			}
+		})
+	}
+}